@@ -0,0 +1,156 @@
+package cacher
+
+import "time"
+
+// SetMany is used to set several key-value pairs to the current
+// Cacher instance at once, taking its lock only once. The pairs
+// are stored with the default TTL of the Cacher.
+func (c *Cacher[C, T]) SetMany(items map[C]T) {
+	c.setMany(items, nil)
+}
+
+// SetManyWithTTL is used to set several key-value pairs to the
+// current Cacher instance at once with a specific TTL, see
+// Cacher.SetWithTTL.
+func (c *Cacher[C, T]) SetManyWithTTL(items map[C]T, ttl time.Duration) {
+	c.setMany(items, &ttl)
+}
+
+func (c *Cacher[C, T]) setMany(items map[C]T, ttl *time.Duration) {
+	c.mutex.Lock()
+	defer c.unlock()
+	for key, val := range items {
+		c.setLocked(key, c.packValue(val, ttl, false))
+	}
+}
+
+// GetMany is used to get values of several keys at once, taking
+// the lock of the Cacher only once. It returns the unexpired
+// key-value pairs which were found, along with the keys which
+// were missing or had expired.
+//
+// Like Get, it deletes expired keys and renews the expiration
+// time of found keys if revaluation mode is on.
+func (c *Cacher[C, T]) GetMany(keys []C) (found map[C]T, missing []C) {
+	found = make(map[C]T, len(keys))
+	type expiredKey struct {
+		key C
+		val *value[T]
+	}
+	var expired []expiredKey
+	now := c.now()
+	c.mutex.RLock()
+	for _, key := range keys {
+		rValue, ok := c.cacheMap[key]
+		if !ok {
+			c.stats.miss(false)
+			missing = append(missing, key)
+			continue
+		}
+		val, isExpired := rValue.get(now)
+		if isExpired {
+			c.stats.miss(true)
+			missing = append(missing, key)
+			expired = append(expired, expiredKey{key, rValue})
+			continue
+		}
+		c.stats.hit()
+		if c.policy != nil {
+			c.policy.OnAccess(key)
+		}
+		found[key] = val
+	}
+	c.mutex.RUnlock()
+	if len(expired) == 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.unlock()
+	for _, e := range expired {
+		// The key may have been overwritten since we read it.
+		if c.cacheMap[e.key] == e.val {
+			c.deleteLocked(e.key, RemovalExpired)
+		}
+	}
+	return
+}
+
+// DeleteMany is used to delete several keys at once, taking the
+// lock of the Cacher only once. Missing keys are ignored.
+func (c *Cacher[C, T]) DeleteMany(keys []C) {
+	c.mutex.Lock()
+	defer c.unlock()
+	for _, key := range keys {
+		c.deleteStoredLocked(key)
+	}
+}
+
+// SetMany is used to set several key-value pairs at once, taking
+// the lock of each shard only once, see Cacher.SetMany.
+func (s *ShardedCacher[K, V]) SetMany(items map[K]V) {
+	for i, part := range s.splitItems(items) {
+		if len(part) != 0 {
+			s.shards[i].SetMany(part)
+		}
+	}
+}
+
+// SetManyWithTTL is used to set several key-value pairs at once
+// with a specific TTL, see Cacher.SetManyWithTTL.
+func (s *ShardedCacher[K, V]) SetManyWithTTL(items map[K]V, ttl time.Duration) {
+	for i, part := range s.splitItems(items) {
+		if len(part) != 0 {
+			s.shards[i].SetManyWithTTL(part, ttl)
+		}
+	}
+}
+
+// GetMany is used to get values of several keys at once, taking
+// the lock of each shard only once, see Cacher.GetMany.
+func (s *ShardedCacher[K, V]) GetMany(keys []K) (found map[K]V, missing []K) {
+	found = make(map[K]V, len(keys))
+	for i, part := range s.splitKeys(keys) {
+		if len(part) == 0 {
+			continue
+		}
+		f, m := s.shards[i].GetMany(part)
+		for key, val := range f {
+			found[key] = val
+		}
+		missing = append(missing, m...)
+	}
+	return
+}
+
+// DeleteMany is used to delete several keys at once, taking the
+// lock of each shard only once, see Cacher.DeleteMany.
+func (s *ShardedCacher[K, V]) DeleteMany(keys []K) {
+	for i, part := range s.splitKeys(keys) {
+		if len(part) != 0 {
+			s.shards[i].DeleteMany(part)
+		}
+	}
+}
+
+// splitKeys groups the input keys by the index of their shard.
+func (s *ShardedCacher[K, V]) splitKeys(keys []K) [][]K {
+	parts := make([][]K, len(s.shards))
+	for _, key := range keys {
+		i := s.shardIndex(key)
+		parts[i] = append(parts[i], key)
+	}
+	return parts
+}
+
+// splitItems groups the input pairs by the index of their shard.
+func (s *ShardedCacher[K, V]) splitItems(items map[K]V) []map[K]V {
+	parts := make([]map[K]V, len(s.shards))
+	for key, val := range items {
+		i := s.shardIndex(key)
+		if parts[i] == nil {
+			parts[i] = make(map[K]V)
+		}
+		parts[i][key] = val
+	}
+	return parts
+}
@@ -0,0 +1,56 @@
+package cacher
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCacher_BatchOps(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	var expired []int
+	c := NewCacher(&NewCacherOpts{Clock: clock},
+		WithOnEvict(func(key int, _ string, reason RemovalReason) {
+			expired = append(expired, key)
+		}),
+	)
+	c.SetMany(map[int]string{1: "a", 2: "b"})
+	c.SetManyWithTTL(map[int]string{3: "c", 4: "d"}, time.Second)
+	clock.Add(time.Second)
+
+	found, missing := c.GetMany([]int{1, 2, 3, 5})
+	if len(found) != 2 || found[1] != "a" || found[2] != "b" {
+		t.Errorf("Cacher.GetMany() found = %v, want map[1:a 2:b]", found)
+	}
+	sort.Ints(missing)
+	if len(missing) != 2 || missing[0] != 3 || missing[1] != 5 {
+		t.Errorf("Cacher.GetMany() missing = %v, want [3 5]", missing)
+	}
+	if len(expired) != 1 || expired[0] != 3 {
+		t.Errorf("keys expired by GetMany = %v, want [3]", expired)
+	}
+
+	c.DeleteMany([]int{1, 2, 6})
+	if n := c.NumKeys(); n != 1 {
+		t.Errorf("Cacher.NumKeys() = %d after DeleteMany, want 1", n)
+	}
+}
+
+func TestShardedCacher_BatchOps(t *testing.T) {
+	s := NewShardedCacher[int, int](4, nil, nil)
+	items := make(map[int]int)
+	keys := make([]int, 0, 100)
+	for i := 0; i < 100; i++ {
+		items[i] = i
+		keys = append(keys, i)
+	}
+	s.SetMany(items)
+	found, missing := s.GetMany(append(keys, 100))
+	if len(found) != 100 || len(missing) != 1 {
+		t.Errorf("ShardedCacher.GetMany() found %d, missing %d, want 100, 1", len(found), len(missing))
+	}
+	s.DeleteMany(keys[:50])
+	if n := s.NumKeys(); n != 50 {
+		t.Errorf("ShardedCacher.NumKeys() = %d after DeleteMany, want 50", n)
+	}
+}
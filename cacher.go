@@ -1,6 +1,7 @@
 package cacher
 
 import (
+	"fmt"
 	"sync"
 	"time"
 )
@@ -22,12 +23,40 @@ import (
 // method, its expiry will be renewed and this will allow us to
 // keep frequently used keys in the map without expiration.
 type Cacher[C comparable, T any] struct {
-	mutex          *sync.RWMutex
-	status         status
-	cacheMap       map[C]*value[T]
-	cleanInterval  time.Duration
-	cleanerMode    CleaningMode
-	evictionPolicy EvictionPolicy
+	mutex            *sync.RWMutex
+	status           status
+	cacheMap         map[C]*value[T]
+	cleanInterval    time.Duration
+	cleanerMode      CleaningMode
+	revaluate        bool
+	ttl              time.Duration
+	maxKeys          int
+	policy           EvictionPolicy[C]
+	loader           LoaderFunc[C, T]
+	loads            *flightGroup[C, T]
+	pending          map[C]*pendingLoad
+	onEvict          RemovalFunc[C, T]
+	onRemove         RemovalFunc[C, T]
+	removed          []removal[C, T]
+	notifyOnClose    bool
+	stop             chan struct{}
+	stats            *stats
+	codec            Codec
+	clock            Clock
+	refreshAfter     time.Duration
+	earlyRefreshBeta float64
+	ttlJitter        float64
+	cleanBatch       int
+	cleanBudget      time.Duration
+	cleanSample      int
+	cleanKeys        []C
+	cleanPos         int
+	index            *keyIndex
+	store            *storeWriter[C, T]
+	writes           []storeWrite[C, T]
+	// writeSeq is the ticket of the next write lock which hands
+	// changes to the Store, see unlock.
+	writeSeq uint64
 }
 
 // NewCacherOpts defines the optional configuration parameters
@@ -43,7 +72,8 @@ type Cacher[C comparable, T any] struct {
 //
 // CleanInterval (time.Duration):
 // Defines how often the cleaner runs to remove expired entries.
-// Each cleaner run scans the cache and deletes expired keys.
+// Each cleaner run scans the cache and deletes expired keys, see
+// CleanBatchSize and CleanSampleSize to bound the cost of a run.
 // Note: If TimeToLive is set and CleanInterval is not provided,
 // it defaults to half of TimeToLive.
 // Example: If set to 1 hour, the cleaner runs every hour.
@@ -56,6 +86,9 @@ type Cacher[C comparable, T any] struct {
 //     all cache instances in the process.
 //  3. CleaningLocal   – Each cache instance runs its own cleaner.
 //
+// It defaults to CleaningNone, which starts no cleaner: expired
+// keys are then only deleted when they're read.
+//
 // Choose CleaningLocal if you want immediate, instance-specific
 // cleanup without waiting for a central scheduler.
 // Choose CleaningCentral if you want to avoid spawning an extra
@@ -66,11 +99,146 @@ type Cacher[C comparable, T any] struct {
 // When enabled, each successful call to Cacher.Get renews the
 // key’s expiry time, allowing frequently accessed entries to
 // remain cached longer.
+//
+// MaxKeys (int):
+// Caps the number of keys the cache may hold at once.
+// When a Cacher.Set call would exceed this bound, a key chosen
+// by the Eviction mode is evicted to make room for the new one.
+// A value of 0 (the default) leaves the cache unbounded.
+//
+// Eviction (EvictionMode):
+// Selects which key is evicted once MaxKeys is reached.
+// Supported values:
+//  1. EvictionLRU     – Evicts the least recently used key.
+//  2. EvictionLFU     – Evicts the least frequently used key.
+//  3. EvictionTinyLFU – W-TinyLFU, keeps frequently used keys
+//     cached even when a burst of one-off keys is inserted.
+//
+// It defaults to EvictionLRU and is ignored if MaxKeys is 0.
+// Size based eviction works alongside TimeToLive, a key will
+// leave the cache on whichever of them comes first.
+//
+// The settings which depend on the key and value types of the
+// Cacher, ie. a custom eviction policy, the loader, the removal
+// listeners and the Store, are passed to NewCacher as Option
+// values, see WithPolicy, WithLoader, WithOnEvict, WithOnRemove
+// and WithStore.
+//
+// NotifyOnClose (bool):
+// Makes Cacher.Close call the WithOnRemove listener for every key
+// left in the cache with RemovalClosed as the reason, eg. to
+// release resources held by the values.
+//
+// Codec (Codec):
+// The codec used by Cacher.SaveTo and Cacher.LoadFrom to encode
+// snapshots of the cache. It defaults to GobCodec, JSONCodec is
+// also available.
+//
+// Clock (Clock):
+// The source of the current time used for expiring keys.
+// It defaults to SystemClock, use a ManualClock in tests to
+// expire keys without sleeping.
+//
+// RefreshAfterWrite (time.Duration):
+// Once a key is older than this duration, the next Cacher.Get of
+// the key still returns its current value right away, but also
+// reloads it in the background with the loader. It requires a
+// loader, and only one reload per key runs at a time.
+// Used along with TimeToLive, it acts as a soft TTL while the
+// TimeToLive is the hard one: keys are served stale while being
+// refreshed, and only leave the cache if they haven't been read
+// until the TimeToLive passes or their reload keeps failing.
+//
+// TTLJitter (float64):
+// Shortens the TTL of each key stored via Set, SetWithTTL and the
+// loader by a random fraction of up to TTLJitter, eg. with a TTL
+// of 1 hour and a TTLJitter of 0.1 keys expire between 54 and 60
+// minutes after being set. It spreads the expiry of keys which
+// were written at once, eg. after a warm-up, so that they don't
+// all expire in the same cleaner pass.
+//
+// EarlyRefreshBeta (float64):
+// Enables probabilistic early refreshes (XFetch) of loaded keys.
+// As a key gets close to its expiry, each Cacher.Get of the key
+// gets a growing chance to reload it in the background, so that
+// hot keys are refreshed before they expire instead of all their
+// readers missing at once. The chance grows with the time it took
+// to load the key and with EarlyRefreshBeta, 1 is a good default
+// and values above 1 favour earlier refreshes. It requires a
+// loader, and only applies to keys stored by it.
+//
+// CleanBatchSize (int):
+// Makes the cleaner scan the cache in batches of CleanBatchSize
+// keys, releasing the lock in between so that readers and writers
+// aren't stalled by a full scan of a large cache. By default the
+// whole cache is scanned under the lock.
+//
+// CleanTimeBudget (time.Duration):
+// Bounds the time spent in each cleaner run, the run stops after
+// the first batch which exceeds it and the next run resumes the
+// scan from there. If CleanBatchSize isn't set, it
+// defaults to 1024 keys when CleanTimeBudget is set.
+//
+// CleanSampleSize (int):
+// Switches the cleaner to sampling, like Redis does: instead of
+// scanning every key, each run checks CleanSampleSize keys from a
+// random starting point and deletes the expired ones, then samples again as long as at
+// least a quarter of the sampled keys were expired. A run stops
+// after CleanTimeBudget, or 25ms if it isn't set. Expired keys
+// may stay in memory longer than with a full scan, but the cost of
+// a run depends on how many keys are expired rather than on the
+// size of the cache. Expired keys are never returned either way.
+//
+// PrefixIndex (bool):
+// Keeps the keys in an ordered index, so that Cacher.GetByPrefix
+// and Cacher.DeleteByPrefix only visit the keys sharing the input
+// prefix instead of scanning the whole cache. It costs some memory
+// and time on every new and deleted key. It requires the keys to
+// be of type string, NewCacher panics otherwise.
+//
+// WriteBehind (time.Duration):
+// Switches the Store to write-behind: changes are queued and
+// written once per WriteBehind by a goroutine, several changes of
+// the same key being coalesced into the last one. Cacher.Flush
+// writes them right away and Cacher.Close writes them before
+// returning. Queued changes are lost if the process dies.
+//
+// StoreBatchSize (int):
+// Bounds the number of keys per Store.Save and Store.Delete call.
+// With WriteBehind, the queued changes are also written as soon as
+// there are StoreBatchSize of them.
+//
+// StoreRetries (int):
+// Number of times a failed write to the Store is retried, with a
+// doubling delay starting at 50ms, before it's reported to
+// OnStoreError and dropped. In write-through mode, the changes made
+// after a failed write wait for its retries, so that they reach
+// the Store in order.
+//
+// OnStoreError (func(error)):
+// Is called with the errors of the writes to the Store, once the
+// failed writes are dropped. It may call back into the Cacher.
 type NewCacherOpts struct {
-	TimeToLive    time.Duration
-	CleanInterval time.Duration
-	CleanerMode   CleaningMode
-	Revaluate     bool
+	TimeToLive        time.Duration
+	CleanInterval     time.Duration
+	CleanerMode       CleaningMode
+	Revaluate         bool
+	MaxKeys           int
+	Eviction          EvictionMode
+	NotifyOnClose     bool
+	Codec             Codec
+	Clock             Clock
+	RefreshAfterWrite time.Duration
+	TTLJitter         float64
+	EarlyRefreshBeta  float64
+	CleanBatchSize    int
+	CleanTimeBudget   time.Duration
+	CleanSampleSize   int
+	PrefixIndex       bool
+	WriteBehind       time.Duration
+	StoreBatchSize    int
+	StoreRetries      int
+	OnStoreError      func(error)
 }
 
 var centralCleaner *cleaner = newCleaner()
@@ -95,34 +263,85 @@ var centralCleaner *cleaner = newCleaner()
 // It contains optional parameters which you can use while creating
 // a new Cacher instance.
 //
+// options (type ...Option[KeyT, ValueT]):
+// They set the optional settings which depend on KeyT and ValueT,
+// eg. WithLoader.
+//
 // General Example:
 // c := cacher.NewCacher[int, string](&cacher.NewCacherOpts{10*time.Minute, time.Hour, true})
 // will create a new Cacher instance which will expire keys after 10
 // minutes of their addition to the system, all the expired keys will
 // be deleted from cache once in an hour. Keys will have their expiry
 // revalueted on every c.Get call.
-func NewCacher[KeyT comparable, ValueT any](opts *NewCacherOpts) *Cacher[KeyT, ValueT] {
+func NewCacher[KeyT comparable, ValueT any](opts *NewCacherOpts, options ...Option[KeyT, ValueT]) *Cacher[KeyT, ValueT] {
 	if opts == nil {
 		opts = new(NewCacherOpts)
 	}
-	ttl := int64(opts.TimeToLive.Seconds())
-	eviction := DefaultEvictionPolicy(opts.Revaluate, ttl)
+	c := newCacher(opts, options...)
+	c.startCleaner()
+	return c
+}
+
+// newCacher creates a new Cacher instance without starting
+// its cleaner.
+func newCacher[KeyT comparable, ValueT any](opts *NewCacherOpts, options ...Option[KeyT, ValueT]) *Cacher[KeyT, ValueT] {
+	typed := newTypedOpts(options)
 	c := Cacher[KeyT, ValueT]{
-		cacheMap:       make(map[KeyT]*value[ValueT]),
-		mutex:          new(sync.RWMutex),
-		cleanInterval:  opts.CleanInterval,
-		cleanerMode:    opts.CleanerMode,
-		evictionPolicy: eviction,
-	}
-	if eviction != nil {
-		if c.cleanInterval == 0 {
-			c.cleanInterval = 1 * time.Hour
+		cacheMap:         make(map[KeyT]*value[ValueT]),
+		mutex:            new(sync.RWMutex),
+		cleanInterval:    opts.CleanInterval,
+		cleanerMode:      opts.CleanerMode,
+		revaluate:        opts.Revaluate,
+		ttl:              opts.TimeToLive,
+		maxKeys:          opts.MaxKeys,
+		loader:           typed.loader,
+		loads:            newFlightGroup[KeyT, ValueT](),
+		onEvict:          typed.onEvict,
+		onRemove:         typed.onRemove,
+		notifyOnClose:    opts.NotifyOnClose,
+		stats:            new(stats),
+		codec:            opts.Codec,
+		clock:            opts.Clock,
+		refreshAfter:     opts.RefreshAfterWrite,
+		earlyRefreshBeta: opts.EarlyRefreshBeta,
+		ttlJitter:        opts.TTLJitter,
+		cleanBatch:       opts.CleanBatchSize,
+		cleanBudget:      opts.CleanTimeBudget,
+		cleanSample:      opts.CleanSampleSize,
+	}
+	// A jitter above 1 would make TTLs negative.
+	if c.ttlJitter > 1 {
+		c.ttlJitter = 1
+	}
+	if typed.store != nil {
+		if c.loader == nil {
+			c.loader = typed.store.Load
 		}
-		if c.cleanerMode == CleaningCentral {
-			centralCleaner.Register(&c)
-		} else {
-			go c.cleaner()
+		c.store = newStoreWriter(typed.store, opts)
+	}
+	if c.clock == nil {
+		c.clock = SystemClock
+	}
+	if c.codec == nil {
+		c.codec = GobCodec
+	}
+	switch {
+	case typed.policy != nil:
+		c.policy = typed.policy
+	case c.maxKeys > 0:
+		c.policy = NewEvictionPolicy[KeyT](opts.Eviction, c.maxKeys)
+	}
+	if c.cleanInterval == 0 {
+		c.cleanInterval = 1 * time.Hour
+	}
+	if opts.PrefixIndex {
+		if _, ok := any(*new(KeyT)).(string); !ok {
+			panic(fmt.Sprintf("cacher.NewCacher: NewCacherOpts.PrefixIndex requires string keys, not %T", *new(KeyT)))
 		}
+		c.index = newKeyIndex()
+	}
+	if c.cleanBudget > 0 && c.cleanBatch <= 0 {
+		c.cleanBatch = defaultCleanBatch
 	}
 	return &c
 }
@@ -139,8 +358,7 @@ func (c *Cacher[C, T]) Set(key C, val T) {
 // this function will override the default TTL of current Cacher instance
 // for this pair specifically.
 func (c *Cacher[C, T]) SetWithTTL(key C, val T, ttl time.Duration) {
-	var _ttl = int64(ttl.Seconds())
-	c.setRawValue(key, c.packValue(val, &_ttl, false))
+	c.setRawValue(key, c.packValue(val, &ttl, false))
 }
 
 // SetPermanent is used to set a new key-value pair permanently to the
@@ -153,8 +371,90 @@ func (c *Cacher[C, T]) SetPermanent(key C, val T) {
 
 func (c *Cacher[C, T]) setRawValue(key C, val *value[T]) {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	defer c.unlock()
+	c.setLocked(key, val)
+}
+
+// setLocked stores the input value and evicts keys if the cache
+// grew beyond its bound. Caller must hold the write lock.
+func (c *Cacher[C, T]) setLocked(key C, val *value[T]) {
+	if c.status == cacherDeleted {
+		return
+	}
+	c.invalidateLoadLocked(key)
+	c.stats.set()
+	if old, ok := c.cacheMap[key]; ok {
+		c.stats.removed(RemovalReplaced, 1)
+		c.recordLocked(key, old.val, RemovalReplaced)
+	} else if c.index != nil {
+		c.index.insert(any(key).(string))
+	}
 	c.cacheMap[key] = val
+	if !val.synced {
+		c.queueWriteLocked(storeWrite[C, T]{key: key, val: val.val})
+	}
+	if c.policy == nil {
+		return
+	}
+	c.policy.OnInsert(key)
+	if c.maxKeys <= 0 {
+		return
+	}
+	for len(c.cacheMap) > c.maxKeys {
+		victim, ok := c.policy.Victim()
+		if !ok {
+			break
+		}
+		if _, ok = c.cacheMap[victim]; !ok {
+			// Don't spin on a policy which lost track of the keys.
+			c.policy.OnDelete(victim)
+			break
+		}
+		c.deleteLocked(victim, RemovalCapacity)
+	}
+}
+
+// deleteLocked removes the input key from the cache map, lets
+// the eviction policy forget about it and queues the removal for
+// the listeners. Caller must hold the write lock.
+func (c *Cacher[C, T]) deleteLocked(key C, reason RemovalReason) {
+	val, ok := c.cacheMap[key]
+	if !ok {
+		return
+	}
+	delete(c.cacheMap, key)
+	if c.index != nil {
+		c.index.remove(any(key).(string))
+	}
+	if reason == RemovalDeleted {
+		c.invalidateLoadLocked(key)
+		c.queueWriteLocked(storeWrite[C, T]{key: key, deleted: true})
+	}
+	c.stats.removed(reason, 1)
+	c.recordLocked(key, val.val, reason)
+	if c.policy != nil {
+		c.policy.OnDelete(key)
+	}
+}
+
+// getLocked looks up the input key like Get does, but with the
+// write lock held by the caller. Expired keys are deleted.
+func (c *Cacher[C, T]) getLocked(key C, now int64) (*value[T], bool) {
+	rValue, ok := c.cacheMap[key]
+	if !ok {
+		c.stats.miss(false)
+		return nil, false
+	}
+	if _, expired := rValue.get(now); expired {
+		c.stats.miss(true)
+		c.deleteLocked(key, RemovalExpired)
+		return nil, false
+	}
+	c.stats.hit()
+	if c.policy != nil {
+		c.policy.OnAccess(key)
+	}
+	return rValue, true
 }
 
 // Get is used to get value of the input key. It returns
@@ -165,20 +465,47 @@ func (c *Cacher[C, T]) setRawValue(key C, val *value[T]) {
 // key which is retrieved if revaluation mode is on for
 // current Cacher instance.
 func (c *Cacher[C, T]) Get(key C) (value T, ok bool) {
+	value, status := c.Lookup(key)
+	if status != LookupHit {
+		var zero T
+		return zero, false
+	}
+	return value, true
+}
+
+// Lookup is used to get value of the input key like Get does,
+// but it tells apart keys which are missing from keys which have
+// expired. It returns the value of the key along with LookupHit
+// if it was found, its stale value along with LookupExpired if it
+// has expired, or an empty value along with LookupMiss otherwise.
+//
+// Expired keys are deleted from the cache just like Get does, so
+// only the first lookup after the expiry reports LookupExpired,
+// unless the cleaner has removed the key already.
+func (c *Cacher[C, T]) Lookup(key C) (value T, status LookupStatus) {
 	rValue, ok := c.getRawValue(key)
 	if !ok {
-		return
+		c.stats.miss(false)
+		return value, LookupMiss
 	}
-	val, expired := rValue.get()
+	now := c.now()
+	val, expired := rValue.get(now)
 	if !expired {
-		value = val
-		return
+		c.stats.hit()
+		if c.policy != nil {
+			c.policy.OnAccess(key)
+		}
+		c.maybeRefresh(key, rValue, now)
+		return val, LookupHit
 	}
-	ok = false
+	c.stats.miss(true)
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	delete(c.cacheMap, key)
-	return
+	defer c.unlock()
+	// The key may have been overwritten since we read it.
+	if c.cacheMap[key] == rValue {
+		c.deleteLocked(key, RemovalExpired)
+	}
+	return rValue.val, LookupExpired
 }
 
 // GetAll is used to return all the unexpired key-value
@@ -189,14 +516,15 @@ func (c *Cacher[C, T]) Get(key C) (value T, ok bool) {
 // even if the revaluation mode is turned on for the
 // current Cacher instance.
 func (c *Cacher[C, T]) GetAll() []T {
+	now := c.now()
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
-	res := make([]T, len(c.cacheMap))
-	var i = 0
+	res := make([]T, 0, len(c.cacheMap))
 	for _, rv := range c.cacheMap {
-		v := rv.getWithoutExpiry()
-		res[i] = v
-		i++
+		if rv.isExpired(now) {
+			continue
+		}
+		res = append(res, rv.getWithoutExpiry())
 	}
 	return res
 }
@@ -206,33 +534,62 @@ func (c *Cacher[C, T]) GetAll() []T {
 // GetSome function.
 type SegrigatorFunc[T any] func(value T) bool
 
-// GetSome is used to get keys which satisfired a particular
-// condition determined via SegrigatorFunc.
+// EntrySegrigatorFunc takes the input as key and value of
+// current pair. Returned boolean is used for segrigation of
+// pairs for GetSomeEntries and DeleteSomeEntries functions.
+type EntrySegrigatorFunc[C comparable, T any] func(key C, value T) bool
+
+// GetSome is used to get unexpired keys which satisfired a
+// particular condition determined via SegrigatorFunc.
 // It returns those values which satisfied the condition
 // determined via SegrigatorFunc.
+//
+// Note: It doesn't renew expiration time of any key
+// even if the revaluation mode is turned on for the
+// current Cacher instance.
 func (c *Cacher[C, T]) GetSome(cond SegrigatorFunc[T]) []T {
-	if cond == nil {
-		cond = func(T) bool { return true }
-	}
-	return c.getSome(cond)
-}
-
-// The inner function of GetSome
-func (c *Cacher[C, T]) getSome(cond SegrigatorFunc[T]) []T {
 	// we can't determine length yet due to segrigations by the
 	// cond function.
 	res := []T{}
+	c.getSome(func(_ C, v T) {
+		if cond == nil || cond(v) {
+			res = append(res, v)
+		}
+	})
+	return res
+}
+
+// GetSomeEntries is used to get unexpired key-value pairs which
+// satisfy a particular condition determined via
+// EntrySegrigatorFunc, which unlike SegrigatorFunc also gets
+// the key of each pair. It returns those pairs as a map.
+//
+// Note: It doesn't renew expiration time of any key
+// even if the revaluation mode is turned on for the
+// current Cacher instance.
+func (c *Cacher[C, T]) GetSomeEntries(cond EntrySegrigatorFunc[C, T]) map[C]T {
+	res := make(map[C]T)
+	c.getSome(func(k C, v T) {
+		if cond == nil || cond(k, v) {
+			res[k] = v
+		}
+	})
+	return res
+}
+
+// The inner function of GetSome and GetSomeEntries, it calls
+// fn for every unexpired pair under the read lock.
+func (c *Cacher[C, T]) getSome(fn func(key C, val T)) {
+	now := c.now()
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
-	for _, rv := range c.cacheMap {
-		// No need to pass actual ttl since we ain't revaluating
-		v := rv.getWithoutExpiry()
-		if !cond(v) {
+	for k, rv := range c.cacheMap {
+		if rv.isExpired(now) {
 			continue
 		}
-		res = append(res, v)
+		// No need to pass actual ttl since we ain't revaluating
+		fn(k, rv.getWithoutExpiry())
 	}
-	return res
 }
 
 // It returns the value of a key in the form of Value struct.
@@ -244,27 +601,20 @@ func (c *Cacher[C, T]) getRawValue(key C) (val *value[T], ok bool) {
 }
 
 // It packs the value to a a struct with expiry date.
-func (c *Cacher[C, T]) packValue(val T, ttl *int64, permanent bool) *value[T] {
-	ev := c.evictionPolicy.getEvictableValue()
-	if dv, ok := ev.(*defaultEviction); ok {
-		if ttl != nil {
-			var _ttl_val = *ttl
-			if _ttl_val != 0 {
-				dv.expiry = time.Now().Unix() + _ttl_val
-			}
-		} else {
-			if dv.ttl != 0 {
-				dv.expiry = time.Now().Unix() + dv.ttl
-			}
-		}
-		if permanent {
-			dv.expiry = 0
-		}
-		ev = dv
-	}
+func (c *Cacher[C, T]) packValue(val T, ttl *time.Duration, permanent bool) *value[T] {
+	now := c.now()
 	v := value[T]{
-		val:            val,
-		evictibleValue: ev,
+		created:   now,
+		ttl:       c.ttl,
+		revaluate: c.revaluate,
+		permanent: permanent,
+		val:       val,
+	}
+	if ttl != nil {
+		v.ttl = *ttl
+	}
+	if v.ttl != 0 && !permanent {
+		v.expiry = now + int64(c.jitter(v.ttl))
 	}
 	return &v
 }
@@ -274,27 +624,36 @@ func (c *Cacher[C, T]) packValue(val T, ttl *int64, permanent bool) *value[T] {
 // is no such key, Delete is a no-op.
 func (c *Cacher[C, T]) Delete(key C) {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	delete(c.cacheMap, key)
+	defer c.unlock()
+	c.deleteStoredLocked(key)
 }
 
 // DeleteSome is used to delete keys which satisfied a
 // particular condition determined via SegrigatorFunc.
 func (c *Cacher[C, T]) DeleteSome(cond SegrigatorFunc[T]) {
-	if cond == nil {
-		cond = func(T) bool { return true }
-	}
-	c.deleteSome(cond)
+	c.deleteSome(func(_ C, v T) bool {
+		return cond == nil || cond(v)
+	})
 }
 
-func (c *Cacher[C, T]) deleteSome(cond SegrigatorFunc[T]) {
+// DeleteSomeEntries is used to delete key-value pairs which
+// satisfy a particular condition determined via
+// EntrySegrigatorFunc, which unlike SegrigatorFunc also gets the
+// key of each pair.
+func (c *Cacher[C, T]) DeleteSomeEntries(cond EntrySegrigatorFunc[C, T]) {
+	c.deleteSome(func(k C, v T) bool {
+		return cond == nil || cond(k, v)
+	})
+}
+
+func (c *Cacher[C, T]) deleteSome(cond EntrySegrigatorFunc[C, T]) {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	defer c.unlock()
 	for k, v := range c.cacheMap {
-		if !cond(v.val) {
+		if !cond(k, v.val) {
 			continue
 		}
-		delete(c.cacheMap, k)
+		c.deleteLocked(k, RemovalDeleted)
 	}
 }
 
@@ -303,10 +662,78 @@ func (c *Cacher[C, T]) deleteSome(cond SegrigatorFunc[T]) {
 // Use it if you want to delete all keys at once.
 // It doesn't return anything.
 func (c *Cacher[C, T]) Reset() {
-	c.status = cacherReset
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	defer c.unlock()
+	if c.status == cacherDeleted {
+		return
+	}
+	c.status = cacherReset
+	c.clearLocked(RemovalReset)
+}
+
+// clearLocked drops every key of the cache map. Caller must hold
+// the write lock.
+func (c *Cacher[C, T]) clearLocked(reason RemovalReason) {
+	for _, p := range c.pending {
+		p.stale = true
+	}
+	c.stats.removed(reason, len(c.cacheMap))
+	if c.policy != nil || c.hasListeners() {
+		for key, val := range c.cacheMap {
+			c.recordLocked(key, val.val, reason)
+			if c.policy != nil {
+				c.policy.OnDelete(key)
+			}
+		}
+	}
 	c.cacheMap = make(map[C]*value[T])
+	if c.index != nil {
+		c.index = newKeyIndex()
+	}
+}
+
+// Close stops the cleaner of current Cacher instance, unregisters
+// it from the central cleaner and drops all of its keys. The
+// listeners are only called for the dropped keys if
+// NewCacherOpts.NotifyOnClose was set.
+//
+// Once closed, the Cacher behaves as an empty cache: Set calls
+// are no-ops, Get calls miss and GetOrLoad returns ErrCacherClosed.
+// The conditional setters, eg. SetIfAbsent, report that nothing
+// was stored, and Update doesn't call its UpdateFunc.
+// Calling Close again returns ErrCacherClosed.
+func (c *Cacher[C, T]) Close() error {
+	c.mutex.Lock()
+	if c.status == cacherDeleted {
+		c.mutex.Unlock()
+		return ErrCacherClosed
+	}
+	c.status = cacherDeleted
+	if c.notifyOnClose {
+		c.clearLocked(RemovalClosed)
+	} else {
+		c.stats.removed(RemovalClosed, len(c.cacheMap))
+		c.removed = nil
+		c.cacheMap = make(map[C]*value[T])
+		if c.index != nil {
+			c.index = newKeyIndex()
+		}
+	}
+	seq := c.writeSeq
+	c.unlock()
+	c.stopCleaner()
+	if c.store != nil {
+		c.store.close(seq)
+	}
+	return nil
+}
+
+// Closed reports whether Close was called on current Cacher
+// instance.
+func (c *Cacher[C, T]) Closed() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.status == cacherDeleted
 }
 
 // NumKeys counts the number of keys present in the
@@ -322,16 +749,11 @@ func (c *Cacher[C, T]) getCleanInterval() time.Duration {
 }
 
 func (c *Cacher[C, T]) cleanExpired() {
-	c.mutex.Lock()
-	for key, val := range c.cacheMap {
-		// Skip the current clean window if cacher is reset or deleted.
-		if c.status == cacherReset || c.status == cacherDeleted {
-			c.status = noop
-			break
-		}
-		if val.isExpired(true) {
-			delete(c.cacheMap, key)
-		}
+	start := time.Now()
+	defer func() { c.stats.cleaned(time.Since(start)) }()
+	if c.cleanSample > 0 {
+		c.sampleExpired(start)
+		return
 	}
-	c.mutex.Unlock()
+	c.scanExpired(start)
 }
@@ -0,0 +1,130 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacher_MaxKeysLRU(t *testing.T) {
+	c := NewCacher[int, string](&NewCacherOpts{MaxKeys: 2})
+	c.Set(1, "one")
+	c.Set(2, "two")
+	// Touching 1 makes 2 the least recently used key.
+	if _, ok := c.Get(1); !ok {
+		t.Fatalf("Cacher.Get(1) missed before eviction")
+	}
+	c.Set(3, "three")
+	if n := c.NumKeys(); n != 2 {
+		t.Errorf("Cacher.NumKeys() = %d, want 2", n)
+	}
+	if _, ok := c.Get(2); ok {
+		t.Errorf("Cacher.Get(2) hit, want it evicted")
+	}
+	for _, key := range []int{1, 3} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("Cacher.Get(%d) missed, want hit", key)
+		}
+	}
+}
+
+func TestCacher_Close(t *testing.T) {
+	var closed []int
+	c := NewCacher(&NewCacherOpts{CleanerMode: CleaningCentral, NotifyOnClose: true},
+		WithOnRemove(func(key int, _ string, reason RemovalReason) {
+			if reason == RemovalClosed {
+				closed = append(closed, key)
+			}
+		}),
+	)
+	c.Set(1, "one")
+	if err := c.Close(); err != nil {
+		t.Fatalf("Cacher.Close() error = %v", err)
+	}
+	if err := c.Close(); err != ErrCacherClosed {
+		t.Errorf("second Cacher.Close() error = %v, want %v", err, ErrCacherClosed)
+	}
+	if len(closed) != 1 || closed[0] != 1 {
+		t.Errorf("keys notified on close = %v, want [1]", closed)
+	}
+	if centralCleaner.registered(c) {
+		t.Errorf("closed Cacher is still registered to the central cleaner")
+	}
+
+	c.Set(2, "two")
+	if _, ok := c.Get(2); ok {
+		t.Errorf("Cacher.Get(2) hit on a closed cacher")
+	}
+	if _, err := c.GetOrLoad(3, func(int) (string, error) { return "three", nil }); err != ErrCacherClosed {
+		t.Errorf("Cacher.GetOrLoad() error = %v, want %v", err, ErrCacherClosed)
+	}
+	if c.SetIfAbsent(4, "four") {
+		t.Errorf("Cacher.SetIfAbsent() = true on a closed cacher")
+	}
+	if _, loaded := c.GetOrSet(4, "four"); !loaded {
+		t.Errorf("Cacher.GetOrSet() stored a value on a closed cacher")
+	}
+	if _, ok := c.Update(4, func(string, bool) (string, bool) {
+		t.Errorf("Cacher.Update() called fn on a closed cacher")
+		return "four", true
+	}); ok {
+		t.Errorf("Cacher.Update() = true on a closed cacher")
+	}
+	if !c.Closed() {
+		t.Errorf("Cacher.Closed() = false after Close")
+	}
+}
+
+func TestCacher_Lookup(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := NewCacher[int, string](&NewCacherOpts{TimeToLive: time.Second, Clock: clock})
+	c.Set(1, "one")
+	if val, status := c.Lookup(1); status != LookupHit || val != "one" {
+		t.Errorf("Cacher.Lookup() = %q, %s, want one, hit", val, status)
+	}
+	clock.Add(time.Second)
+	if val, status := c.Lookup(1); status != LookupExpired || val != "one" {
+		t.Errorf("Cacher.Lookup() = %q, %s, want stale one, expired", val, status)
+	}
+	if val, status := c.Lookup(1); status != LookupMiss || val != "" {
+		t.Errorf("Cacher.Lookup() = %q, %s, want empty, miss", val, status)
+	}
+	if s := c.Stats(); s.Misses != 2 || s.ExpiredOnRead != 1 {
+		t.Errorf("Stats() misses, expired = %d, %d, want 2, 1", s.Misses, s.ExpiredOnRead)
+	}
+}
+
+func TestCacher_BulkReadsHonourExpiry(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := NewCacher[int, string](&NewCacherOpts{Clock: clock})
+	c.SetWithTTL(1, "muted", time.Minute)
+	c.SetWithTTL(2, "muted", time.Hour)
+	c.Set(3, "banned")
+	clock.Add(time.Minute)
+
+	if got := c.GetAll(); len(got) != 2 {
+		t.Errorf("Cacher.GetAll() = %v, want 2 unexpired values", got)
+	}
+	if got := c.GetSome(func(v string) bool { return v == "muted" }); len(got) != 1 {
+		t.Errorf("Cacher.GetSome() = %v, want [muted]", got)
+	}
+	got := c.GetSomeEntries(func(k int, v string) bool { return v == "muted" })
+	if len(got) != 1 || got[2] != "muted" {
+		t.Errorf("Cacher.GetSomeEntries() = %v, want map[2:muted]", got)
+	}
+
+	c.DeleteSomeEntries(func(k int, _ string) bool { return k >= 2 })
+	if _, ok := c.Peek(2); ok {
+		t.Errorf("Cacher.DeleteSomeEntries() didn't delete key 2")
+	}
+	if _, ok := c.Peek(3); ok {
+		t.Errorf("Cacher.DeleteSomeEntries() didn't delete key 3")
+	}
+	if n := c.NumKeys(); n != 1 {
+		t.Errorf("Cacher.NumKeys() = %d, want 1", n)
+	}
+	// Unlike reads, deletes match expired keys too.
+	c.DeleteSome(func(v string) bool { return v == "muted" })
+	if n := c.NumKeys(); n != 0 {
+		t.Errorf("Cacher.DeleteSome() left %d expired keys, want 0", n)
+	}
+}
@@ -1,6 +1,7 @@
 package cacher
 
 import (
+	"container/heap"
 	"sync"
 	"time"
 )
@@ -10,72 +11,331 @@ type cleanable interface {
 	getCleanInterval() time.Duration
 }
 
+// cleaner cleans the registered cachers once per their own clean
+// interval from a single goroutine. The cachers are kept in a
+// min-heap ordered by the next time they're due, so the goroutine
+// only wakes up when the earliest of them is due.
 type cleaner struct {
-	mu            sync.RWMutex
-	cachers       []cleanable
-	cleanInterval time.Duration
-	once          sync.Once
+	mu      sync.Mutex
+	queue   cleanerQueue
+	entries map[cleanable]*cleanerEntry
+	// wake is signalled when the earliest due time may have changed.
+	wake chan struct{}
+	once sync.Once
+}
+
+type cleanerEntry struct {
+	c        cleanable
+	interval time.Duration
+	next     time.Time
+	// index is the position of the entry in the queue, it's
+	// maintained by the heap.Interface methods.
+	index int
+}
+
+// cleanerQueue implements heap.Interface, ordered by next due time.
+type cleanerQueue []*cleanerEntry
+
+func (q cleanerQueue) Len() int           { return len(q) }
+func (q cleanerQueue) Less(i, j int) bool { return q[i].next.Before(q[j].next) }
+
+func (q cleanerQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *cleanerQueue) Push(x any) {
+	e := x.(*cleanerEntry)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *cleanerQueue) Pop() any {
+	old := *q
+	n := len(old) - 1
+	e := old[n]
+	old[n] = nil
+	*q = old[:n]
+	e.index = -1
+	return e
 }
 
 func newCleaner() *cleaner {
 	return &cleaner{
-		cachers: make([]cleanable, 0),
+		entries: make(map[cleanable]*cleanerEntry),
+		wake:    make(chan struct{}, 1),
 	}
 }
 
-// A function to help find GCD of 2 time Durations
-func gcd(a, b time.Duration) time.Duration {
-	for b != 0 {
-		a, b = b, a%b
+// Register starts cleaning the input cacher once per its clean
+// interval, the first clean happens one interval from now.
+// Registering a cacher again only reschedules it.
+func (cl *cleaner) Register(c cleanable) {
+	cl.register(c, time.Now())
+	cl.once.Do(cl.Run)
+}
+
+func (cl *cleaner) register(c cleanable, now time.Time) {
+	interval := c.getCleanInterval()
+	if interval <= 0 {
+		interval = time.Second
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if e, ok := cl.entries[c]; ok {
+		e.interval = interval
+		e.next = now.Add(interval)
+		heap.Fix(&cl.queue, e.index)
+	} else {
+		e = &cleanerEntry{c: c, interval: interval, next: now.Add(interval)}
+		cl.entries[c] = e
+		heap.Push(&cl.queue, e)
 	}
-	return a
+	cl.signal()
 }
 
-func (cl *cleaner) calculateIntervalGCD() {
-	if len(cl.cachers) == 0 {
-		cl.cleanInterval = 0
+// Unregister stops cleaning the input cacher, it's a no-op if
+// the cacher isn't registered.
+func (cl *cleaner) Unregister(c cleanable) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	e, ok := cl.entries[c]
+	if !ok {
 		return
 	}
-	interval := cl.cachers[0].getCleanInterval()
-	for _, c := range cl.cachers[1:] {
-		interval = gcd(interval, c.getCleanInterval())
+	delete(cl.entries, c)
+	heap.Remove(&cl.queue, e.index)
+	cl.signal()
+}
+
+// registered reports whether the input cacher is registered.
+func (cl *cleaner) registered(c cleanable) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	_, ok := cl.entries[c]
+	return ok
+}
+
+func (cl *cleaner) signal() {
+	select {
+	case cl.wake <- struct{}{}:
+	default:
 	}
-	cl.cleanInterval = interval
 }
 
-func (cl *cleaner) Register(c cleanable) {
+// due pops the cachers which are due at the input time and
+// schedules their next clean, it returns them along with the time
+// until the next cacher is due, or 0 if none is registered.
+func (cl *cleaner) due(now time.Time) ([]cleanable, time.Duration) {
 	cl.mu.Lock()
 	defer cl.mu.Unlock()
 
-	cl.cachers = append(cl.cachers, c)
-	cl.calculateIntervalGCD()
-	cl.once.Do(cl.Run)
+	var cachers []cleanable
+	for len(cl.queue) > 0 {
+		e := cl.queue[0]
+		if e.next.After(now) {
+			return cachers, e.next.Sub(now)
+		}
+		cachers = append(cachers, e.c)
+		e.next = now.Add(e.interval)
+		heap.Fix(&cl.queue, 0)
+	}
+	return cachers, 0
 }
 
 func (cl *cleaner) Run() {
 	go func() {
+		timer := time.NewTimer(time.Hour)
 		for {
-			cl.mu.RLock()
-			cachers := append([]cleanable(nil), cl.cachers...)
-			interval := cl.cleanInterval
-			if interval <= 0 {
-				interval = time.Duration(time.Second)
-			}
-			cl.mu.RUnlock()
+			cachers, wait := cl.due(time.Now())
 			for _, c := range cachers {
 				c.cleanExpired()
 			}
-			time.Sleep(interval)
+			if len(cachers) != 0 {
+				// Cleaning took some time, some cachers may be due.
+				continue
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			if wait == 0 {
+				// Nothing is registered, sleep until something is.
+				wait = time.Hour
+			}
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-cl.wake:
+			}
 		}
 	}()
 }
 
+// startCleaner starts cleaning the current Cacher instance as
+// per its cleaning mode, CleaningNone doesn't start anything.
+func (c *Cacher[C, T]) startCleaner() {
+	switch c.cleanerMode {
+	case CleaningNone:
+		return
+	case CleaningCentral:
+		centralCleaner.Register(c)
+		return
+	}
+	c.stop = make(chan struct{})
+	go c.cleaner()
+}
+
+// stopCleaner undoes startCleaner, it must be called only once.
+func (c *Cacher[C, T]) stopCleaner() {
+	if c.cleanerMode == CleaningCentral {
+		centralCleaner.Unregister(c)
+		return
+	}
+	if c.stop != nil {
+		close(c.stop)
+	}
+}
+
 // A function used by current Cacher instance to clean
 // expired keys on regular basis.
 func (c *Cacher[C, T]) cleaner() {
-	ticker := time.NewTicker(c.cleanInterval)
+	runCleaner(c, c.stop)
+}
+
+// runCleaner cleans the input cacher once per its clean interval
+// until the stop channel is closed.
+func runCleaner(c cleanable, stop <-chan struct{}) {
+	ticker := time.NewTicker(c.getCleanInterval())
 	defer ticker.Stop()
-	for range ticker.C {
-		c.cleanExpired()
+	for {
+		select {
+		case <-ticker.C:
+			c.cleanExpired()
+		case <-stop:
+			return
+		}
+	}
+}
+
+const (
+	// defaultCleanBatch is the batch size used when only
+	// NewCacherOpts.CleanTimeBudget is set.
+	defaultCleanBatch = 1024
+	// defaultSampleBudget bounds a sampling run when
+	// NewCacherOpts.CleanTimeBudget isn't set.
+	defaultSampleBudget = 25 * time.Millisecond
+)
+
+// skipCleanLocked reports whether the current clean window must be
+// skipped because the cacher was reset or closed.
+func (c *Cacher[C, T]) skipCleanLocked() bool {
+	switch c.status {
+	case cacherDeleted:
+		return true
+	case cacherReset:
+		c.status = noop
+		return true
+	}
+	return false
+}
+
+// scanExpired deletes the expired keys of current Cacher instance
+// all at once, or in batches of cleanBatch keys if it's set.
+func (c *Cacher[C, T]) scanExpired(start time.Time) {
+	now := c.now()
+	c.mutex.Lock()
+	defer c.unlock()
+	if c.cleanBatch > 0 {
+		c.scanBatchesLocked(start, now)
+		return
+	}
+	if c.skipCleanLocked() {
+		return
+	}
+	for key, val := range c.cacheMap {
+		if val.isExpired(now) {
+			c.deleteLocked(key, RemovalExpired)
+		}
+	}
+}
+
+// scanBatchesLocked goes on with the scan of the keys listed in
+// cleanKeys from cleanPos, in batches of cleanBatch keys, until it
+// ends or the clean budget is exceeded. The next run resumes where
+// it stopped, and once the scan ends the next run lists the keys
+// again. Caller must hold the write lock, it's released in between
+// batches.
+func (c *Cacher[C, T]) scanBatchesLocked(start time.Time, now int64) {
+	if c.cleanPos >= len(c.cleanKeys) {
+		c.cleanKeys = c.cleanKeys[:0]
+		for key := range c.cacheMap {
+			c.cleanKeys = append(c.cleanKeys, key)
+		}
+		c.cleanPos = 0
+	}
+	for n := 1; c.cleanPos < len(c.cleanKeys); n++ {
+		if c.skipCleanLocked() {
+			c.cleanKeys, c.cleanPos = nil, 0
+			return
+		}
+		key := c.cleanKeys[c.cleanPos]
+		c.cleanPos++
+		if val, ok := c.cacheMap[key]; ok && val.isExpired(now) {
+			c.deleteLocked(key, RemovalExpired)
+		}
+		if n%c.cleanBatch != 0 {
+			continue
+		}
+		if c.cleanBudget > 0 && time.Since(start) >= c.cleanBudget {
+			return
+		}
+		// Let readers and writers in between batches, keys deleted
+		// meanwhile are skipped and keys added meanwhile are left to
+		// the next scan.
+		c.unlock()
+		c.mutex.Lock()
+	}
+}
+
+// sampleExpired deletes the expired keys among cleanSample keys of
+// current Cacher instance, and repeats while at least a quarter of
+// them were expired and the clean budget allows it.
+func (c *Cacher[C, T]) sampleExpired(start time.Time) {
+	budget := c.cleanBudget
+	if budget <= 0 {
+		budget = defaultSampleBudget
+	}
+	now := c.now()
+	for {
+		sampled, expired := 0, 0
+		c.mutex.Lock()
+		if c.skipCleanLocked() {
+			c.unlock()
+			return
+		}
+		// Ranging over a map starts at a random key, but the keys
+		// after it are the same from one range to the other, hence
+		// the sample is a random run of keys rather than a uniform
+		// random sample.
+		for key, val := range c.cacheMap {
+			if sampled == c.cleanSample {
+				break
+			}
+			sampled++
+			if val.isExpired(now) {
+				c.deleteLocked(key, RemovalExpired)
+				expired++
+			}
+		}
+		c.unlock()
+		if expired*4 < sampled || expired == 0 || time.Since(start) >= budget {
+			return
+		}
 	}
 }
@@ -0,0 +1,197 @@
+package cacher
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingCleanable struct {
+	interval time.Duration
+	cleans   int32
+}
+
+func (c *countingCleanable) cleanExpired()                   { atomic.AddInt32(&c.cleans, 1) }
+func (c *countingCleanable) getCleanInterval() time.Duration { return c.interval }
+
+func TestCleaner_DueHonoursIntervals(t *testing.T) {
+	cl := newCleaner()
+	now := time.Now()
+	hourly := &countingCleanable{interval: time.Hour}
+	fast := &countingCleanable{interval: 7 * time.Second}
+	cl.register(hourly, now)
+	cl.register(fast, now)
+
+	cachers, wait := cl.due(now)
+	if len(cachers) != 0 || wait != 7*time.Second {
+		t.Fatalf("due(now) = %d cachers, %v, want 0, 7s", len(cachers), wait)
+	}
+	for i := 1; i <= 10; i++ {
+		cachers, _ = cl.due(now.Add(time.Duration(i) * 7 * time.Second))
+		if len(cachers) != 1 || cachers[0] != cleanable(fast) {
+			t.Fatalf("due() at tick %d = %v, want only the 7s cacher", i, cachers)
+		}
+	}
+	cachers, _ = cl.due(now.Add(time.Hour))
+	if len(cachers) != 2 {
+		t.Errorf("due() after 1h = %d cachers, want 2", len(cachers))
+	}
+}
+
+func TestCleaner_RegisterUnregisterMany(t *testing.T) {
+	cl := newCleaner()
+	now := time.Now()
+	rng := rand.New(rand.NewSource(1))
+	all := make([]*countingCleanable, 1000)
+	for i := range all {
+		all[i] = &countingCleanable{interval: time.Duration(1+rng.Intn(3600)) * time.Second}
+		cl.register(all[i], now)
+	}
+	// Register some twice, it must not duplicate them.
+	for _, c := range all[:100] {
+		cl.register(c, now)
+	}
+	removed := make(map[cleanable]bool)
+	for _, i := range rng.Perm(len(all))[:500] {
+		cl.Unregister(all[i])
+		removed[all[i]] = true
+	}
+	cl.Unregister(&countingCleanable{})
+
+	if n := len(cl.queue); n != 500 || len(cl.entries) != 500 {
+		t.Fatalf("cleaner has %d queued and %d entries, want 500", n, len(cl.entries))
+	}
+	for i, e := range cl.queue {
+		if e.index != i {
+			t.Fatalf("entry at %d has index %d", i, e.index)
+		}
+		if removed[e.c] {
+			t.Fatalf("unregistered cacher is still queued")
+		}
+	}
+
+	// Cachers must be handed out only when due.
+	for step := time.Second; step <= time.Hour; step += time.Second {
+		cachers, _ := cl.due(now.Add(step))
+		for _, c := range cachers {
+			if removed[c] {
+				t.Fatalf("unregistered cacher is due")
+			}
+			if interval := c.getCleanInterval(); step%interval != 0 {
+				t.Fatalf("cacher with interval %v due after %v", interval, step)
+			}
+		}
+	}
+}
+
+func TestCleaner_Run(t *testing.T) {
+	cl := newCleaner()
+	fast := &countingCleanable{interval: 5 * time.Millisecond}
+	slow := &countingCleanable{interval: time.Hour}
+	cl.Register(slow)
+	cl.Register(fast)
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&fast.cleans) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("fast cacher was cleaned %d times, want 3", atomic.LoadInt32(&fast.cleans))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&slow.cleans); n != 0 {
+		t.Errorf("hourly cacher was cleaned %d times, want 0", n)
+	}
+
+	cl.Unregister(fast)
+	time.Sleep(10 * time.Millisecond)
+	n := atomic.LoadInt32(&fast.cleans)
+	time.Sleep(20 * time.Millisecond)
+	if m := atomic.LoadInt32(&fast.cleans); m != n {
+		t.Errorf("unregistered cacher was cleaned %d more times", m-n)
+	}
+}
+
+func TestCacher_CleaningNone(t *testing.T) {
+	c := NewCacher[int, int](&NewCacherOpts{CleanerMode: CleaningNone})
+	if c.stop != nil {
+		t.Errorf("NewCacher() started a cleaner with CleaningNone")
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Cacher.Close() error = %v", err)
+	}
+	s := NewShardedCacher[int, int](4, nil, &NewCacherOpts{CleanerMode: CleaningNone})
+	if s.stop != nil {
+		t.Errorf("NewShardedCacher() started a cleaner with CleaningNone")
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("ShardedCacher.Close() error = %v", err)
+	}
+}
+
+func TestCacher_CleanBatches(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts NewCacherOpts
+		want int
+	}{
+		{"full scan", NewCacherOpts{}, 0},
+		{"batches", NewCacherOpts{CleanBatchSize: 10}, 0},
+		// The budget is exceeded by the first batch.
+		{"time budget", NewCacherOpts{CleanBatchSize: 10, CleanTimeBudget: time.Nanosecond}, 90},
+		{"sampling", NewCacherOpts{CleanSampleSize: 20, CleanTimeBudget: time.Minute}, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(time.Now())
+			opts := tt.opts
+			opts.Clock = clock
+			c := newCacher[int, int](&opts)
+			for i := 0; i < 100; i++ {
+				c.SetWithTTL(i, i, time.Second)
+			}
+			clock.Add(time.Second)
+			c.cleanExpired()
+			if n := c.NumKeys(); n != tt.want {
+				t.Errorf("Cacher.NumKeys() after a clean = %d, want %d", n, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacher_CleanResumes(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := newCacher[int, int](&NewCacherOpts{
+		CleanBatchSize:  10,
+		CleanTimeBudget: time.Nanosecond,
+		Clock:           clock,
+	})
+	for i := 0; i < 100; i++ {
+		c.SetWithTTL(i, i, time.Second)
+	}
+	clock.Add(time.Second)
+	// Every run must go on with keys the previous runs didn't reach.
+	for run := 1; run <= 10; run++ {
+		c.cleanExpired()
+		if n, want := c.NumKeys(), 100-10*run; n != want {
+			t.Fatalf("Cacher.NumKeys() after %d runs = %d, want %d", run, n, want)
+		}
+	}
+}
+
+func TestCacher_CleanSamplingStops(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := newCacher[int, int](&NewCacherOpts{CleanSampleSize: 20, Clock: clock})
+	for i := 0; i < 1000; i++ {
+		c.Set(i, i)
+	}
+	c.SetWithTTL(-1, 0, time.Second)
+	clock.Add(time.Second)
+	c.cleanExpired()
+	// A single sample was taken since few keys are expired.
+	if n := c.NumKeys(); n < 1000 {
+		t.Errorf("Cacher.NumKeys() after a clean = %d, want at least 1000", n)
+	}
+	if s := c.Stats(); s.CleanerRuns != 1 {
+		t.Errorf("Stats().CleanerRuns = %d, want 1", s.CleanerRuns)
+	}
+}
@@ -0,0 +1,60 @@
+package cacher
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time to a Cacher, it can be replaced
+// via NewCacherOpts.Clock, eg. by a ManualClock in tests so that
+// they don't need to sleep for keys to expire.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock is the Clock reading the system time, it's the
+// default clock of every Cacher.
+var SystemClock Clock = systemClock{}
+
+// ManualClock is a Clock which only moves when told to, it's
+// safe for concurrent use.
+type ManualClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// NewManualClock creates a new ManualClock set to the input time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (m *ManualClock) Now() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.now
+}
+
+// Add moves the clock forward by the input duration.
+func (m *ManualClock) Add(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+}
+
+// Set moves the clock to the input time.
+func (m *ManualClock) Set(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+// now returns the current time of the clock of the Cacher in
+// nanoseconds since the unix epoch.
+func (c *Cacher[C, T]) now() int64 {
+	return c.clock.Now().UnixNano()
+}
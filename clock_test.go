@@ -0,0 +1,54 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacher_SubSecondTTL(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := NewCacher[string, int](&NewCacherOpts{
+		TimeToLive: 200 * time.Millisecond,
+		Clock:      clock,
+	})
+	c.Set("window", 1)
+	c.SetWithTTL("long", 2, 1900*time.Millisecond)
+
+	clock.Add(199 * time.Millisecond)
+	if _, ok := c.Get("window"); !ok {
+		t.Errorf("key expired before its 200ms TTL")
+	}
+	clock.Add(time.Millisecond)
+	if _, ok := c.Get("window"); ok {
+		t.Errorf("key didn't expire after its 200ms TTL")
+	}
+	clock.Add(1500 * time.Millisecond)
+	if _, ok := c.Get("long"); !ok {
+		t.Errorf("1.9s key expired after 1.7s")
+	}
+	clock.Add(200 * time.Millisecond)
+	if _, ok := c.Get("long"); ok {
+		t.Errorf("1.9s key didn't expire after 1.9s")
+	}
+}
+
+func TestCacher_RevaluateWithClock(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := NewCacher[int, int](&NewCacherOpts{
+		TimeToLive: time.Second,
+		Revaluate:  true,
+		Clock:      clock,
+	})
+	c.Set(1, 1)
+	for i := 0; i < 5; i++ {
+		clock.Add(900 * time.Millisecond)
+		if _, ok := c.Get(1); !ok {
+			t.Fatalf("revaluated key expired after %d renewals", i)
+		}
+	}
+	clock.Add(time.Second)
+	c.cleanExpired()
+	if n := c.NumKeys(); n != 0 {
+		t.Errorf("Cacher.NumKeys() = %d after the cleaner ran, want 0", n)
+	}
+}
@@ -0,0 +1,171 @@
+package cacher
+
+// The following operations are atomic, they run entirely under
+// the write lock of the Cacher so that no other call can sneak in
+// between their read and their write.
+//
+// Keys they add are stored with the default TTL of the Cacher,
+// like Set does. Keys they overwrite keep their expiry and their
+// permanent flag, so that eg. incrementing a counter doesn't
+// extend the window it's counting over.
+
+// GetOrSet is used to get value of the input key if it's present
+// and unexpired, it sets it to the input value otherwise.
+// It returns the value of the key after the call, along with true
+// if that value was already present. A closed Cacher doesn't store
+// the value, it returns it along with true.
+func (c *Cacher[C, T]) GetOrSet(key C, val T) (actual T, loaded bool) {
+	c.mutex.Lock()
+	defer c.unlock()
+	if c.status == cacherDeleted {
+		return val, true
+	}
+	if rValue, ok := c.getLocked(key, c.now()); ok {
+		return rValue.val, true
+	}
+	c.setLocked(key, c.packValue(val, nil, false))
+	return val, false
+}
+
+// SetIfAbsent is used to set a new key-value pair only if the key
+// isn't present or has expired. It returns true if the pair was set,
+// which is never the case once the Cacher is closed.
+func (c *Cacher[C, T]) SetIfAbsent(key C, val T) bool {
+	_, loaded := c.GetOrSet(key, val)
+	return !loaded
+}
+
+// SetIfPresent is used to overwrite the value of the input key only
+// if it's present and unexpired. It returns true if the value was
+// overwritten.
+func (c *Cacher[C, T]) SetIfPresent(key C, val T) bool {
+	c.mutex.Lock()
+	defer c.unlock()
+	rValue, ok := c.getLocked(key, c.now())
+	if !ok {
+		return false
+	}
+	c.setLocked(key, c.repackValue(rValue, val))
+	return true
+}
+
+// CompareAndSwap is used to overwrite the value of the input key
+// with new only if its current value equals old. It returns true
+// if the value was swapped.
+//
+// equal is used to compare the values, if it's nil then they're
+// compared with the == operator, which panics if T isn't comparable.
+func (c *Cacher[C, T]) CompareAndSwap(key C, old, new T, equal func(a, b T) bool) bool {
+	if equal == nil {
+		equal = func(a, b T) bool { return any(a) == any(b) }
+	}
+	c.mutex.Lock()
+	defer c.unlock()
+	rValue, ok := c.getLocked(key, c.now())
+	if !ok || !equal(rValue.val, old) {
+		return false
+	}
+	c.setLocked(key, c.repackValue(rValue, new))
+	return true
+}
+
+// UpdateFunc receives the current value of a key, with ok set to
+// false if the key isn't present or has expired. It returns the
+// new value of the key, and false if the key should be deleted
+// instead.
+type UpdateFunc[T any] func(old T, ok bool) (new T, keep bool)
+
+// Update is used to atomically read, modify and write the value
+// of the input key with the input UpdateFunc, eg. to increment a
+// counter. It returns the new value of the key along with true,
+// or an empty value with false if the key was deleted.
+//
+// fn is called with the lock of the Cacher held, hence it must
+// not call any method of the Cacher. It isn't called once the
+// Cacher is closed.
+func (c *Cacher[C, T]) Update(key C, fn UpdateFunc[T]) (val T, ok bool) {
+	c.mutex.Lock()
+	defer c.unlock()
+	if c.status == cacherDeleted {
+		return
+	}
+	rValue, found := c.getLocked(key, c.now())
+	var old T
+	if found {
+		old = rValue.val
+	}
+	val, ok = fn(old, found)
+	if !ok {
+		if found {
+			c.deleteLocked(key, RemovalDeleted)
+		}
+		var zero T
+		return zero, false
+	}
+	if found {
+		c.setLocked(key, c.repackValue(rValue, val))
+	} else {
+		c.setLocked(key, c.packValue(val, nil, false))
+	}
+	return val, true
+}
+
+// GetAndDelete is used to delete the input key and return the
+// value it had. It returns an empty value with false if the key
+// wasn't present or had expired.
+func (c *Cacher[C, T]) GetAndDelete(key C) (val T, ok bool) {
+	c.mutex.Lock()
+	defer c.unlock()
+	rValue, ok := c.getLocked(key, c.now())
+	if !ok {
+		return
+	}
+	c.deleteLocked(key, RemovalDeleted)
+	return rValue.val, true
+}
+
+// repackValue packs the input value with the expiry settings of
+// an existing one.
+func (c *Cacher[C, T]) repackValue(old *value[T], val T) *value[T] {
+	v := old.clone()
+	v.created = c.now()
+	v.val = val
+	v.synced = false
+	return v
+}
+
+// GetOrSet is used to get value of the input key, or to set it
+// if it's missing, see Cacher.GetOrSet.
+func (s *ShardedCacher[K, V]) GetOrSet(key K, val V) (actual V, loaded bool) {
+	return s.shard(key).GetOrSet(key, val)
+}
+
+// SetIfAbsent is used to set a key only if it's missing, see
+// Cacher.SetIfAbsent.
+func (s *ShardedCacher[K, V]) SetIfAbsent(key K, val V) bool {
+	return s.shard(key).SetIfAbsent(key, val)
+}
+
+// SetIfPresent is used to overwrite a key only if it's present,
+// see Cacher.SetIfPresent.
+func (s *ShardedCacher[K, V]) SetIfPresent(key K, val V) bool {
+	return s.shard(key).SetIfPresent(key, val)
+}
+
+// CompareAndSwap is used to swap the value of a key if it equals
+// old, see Cacher.CompareAndSwap.
+func (s *ShardedCacher[K, V]) CompareAndSwap(key K, old, new V, equal func(a, b V) bool) bool {
+	return s.shard(key).CompareAndSwap(key, old, new, equal)
+}
+
+// Update is used to atomically read, modify and write the value
+// of a key, see Cacher.Update.
+func (s *ShardedCacher[K, V]) Update(key K, fn UpdateFunc[V]) (V, bool) {
+	return s.shard(key).Update(key, fn)
+}
+
+// GetAndDelete is used to delete a key and return its value, see
+// Cacher.GetAndDelete.
+func (s *ShardedCacher[K, V]) GetAndDelete(key K) (V, bool) {
+	return s.shard(key).GetAndDelete(key)
+}
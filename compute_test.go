@@ -0,0 +1,73 @@
+package cacher
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacher_UpdateConcurrentIncrement(t *testing.T) {
+	c := NewCacher[string, int](nil)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				c.Update("hits", func(old int, _ bool) (int, bool) {
+					return old + 1, true
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := c.Get("hits"); v != 4000 {
+		t.Errorf("counter = %d, want 4000", v)
+	}
+	if _, ok := c.Update("hits", func(int, bool) (int, bool) { return 0, false }); ok {
+		t.Errorf("Cacher.Update() kept a key its func asked to delete")
+	}
+	if _, ok := c.Get("hits"); ok {
+		t.Errorf("key still present after Update asked to delete it")
+	}
+}
+
+func TestCacher_AtomicOps(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := NewCacher[int, string](&NewCacherOpts{TimeToLive: time.Minute, Clock: clock})
+
+	if actual, loaded := c.GetOrSet(1, "a"); loaded || actual != "a" {
+		t.Errorf("Cacher.GetOrSet() = %q, %v, want a, false", actual, loaded)
+	}
+	if actual, loaded := c.GetOrSet(1, "b"); !loaded || actual != "a" {
+		t.Errorf("Cacher.GetOrSet() = %q, %v, want a, true", actual, loaded)
+	}
+	if c.SetIfAbsent(1, "b") {
+		t.Errorf("Cacher.SetIfAbsent() overwrote a present key")
+	}
+	if c.SetIfPresent(2, "b") {
+		t.Errorf("Cacher.SetIfPresent() set a missing key")
+	}
+	if c.CompareAndSwap(1, "x", "b", nil) {
+		t.Errorf("Cacher.CompareAndSwap() swapped a mismatching value")
+	}
+	clock.Add(30 * time.Second)
+	if !c.CompareAndSwap(1, "a", "b", nil) {
+		t.Errorf("Cacher.CompareAndSwap() didn't swap a matching value")
+	}
+	// Swapping keeps the original expiry.
+	clock.Add(30 * time.Second)
+	if _, ok := c.Get(1); ok {
+		t.Errorf("swapped key outlived its original expiry")
+	}
+
+	c.SetPermanent(3, "c")
+	c.SetIfPresent(3, "d")
+	clock.Add(time.Hour)
+	if val, ok := c.GetAndDelete(3); !ok || val != "d" {
+		t.Errorf("Cacher.GetAndDelete() = %q, %v, want d, true", val, ok)
+	}
+	if _, ok := c.GetAndDelete(3); ok {
+		t.Errorf("Cacher.GetAndDelete() found a deleted key")
+	}
+}
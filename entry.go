@@ -0,0 +1,186 @@
+package cacher
+
+import "time"
+
+// NoExpiration is returned by Cacher.TTL for keys which never
+// expire.
+const NoExpiration time.Duration = -1
+
+// Entry holds the value of a key along with its metadata, it's
+// returned by Cacher.GetEntry.
+//
+// CreatedAt is the time at which the current value was stored.
+// LastAccess is the time of the last Get which returned the value,
+// it's zero if the value was never read.
+// ExpiresAt is the time at which the key expires, it's zero if the
+// key never expires.
+// Permanent is true for keys set via SetPermanent or Persist.
+type Entry[T any] struct {
+	Value      T
+	CreatedAt  time.Time
+	LastAccess time.Time
+	ExpiresAt  time.Time
+	Permanent  bool
+}
+
+// unixTime converts unix nanoseconds to a time.Time, keeping 0
+// as the zero time.
+func unixTime(nsec int64) time.Time {
+	if nsec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nsec)
+}
+
+// peekLocked returns the unexpired value of the input key without
+// renewing it. Caller must hold the read or the write lock.
+func (c *Cacher[C, T]) peekLocked(key C, now int64) (*value[T], bool) {
+	rValue, ok := c.cacheMap[key]
+	if !ok || rValue.isExpired(now) {
+		return nil, false
+	}
+	return rValue, true
+}
+
+// Peek is used to get value of the input key without side effects:
+// it never renews the expiration time of the key, even if the
+// revaluation mode is on, and isn't counted as an access by the
+// eviction policy or the statistics of the Cacher.
+func (c *Cacher[C, T]) Peek(key C) (value T, ok bool) {
+	now := c.now()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	rValue, ok := c.peekLocked(key, now)
+	if !ok {
+		return
+	}
+	return rValue.val, true
+}
+
+// GetEntry is used to get value of the input key along with its
+// metadata, see Entry. Like Peek, it has no side effects.
+func (c *Cacher[C, T]) GetEntry(key C) (entry Entry[T], ok bool) {
+	now := c.now()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	rValue, ok := c.peekLocked(key, now)
+	if !ok {
+		return
+	}
+	return Entry[T]{
+		Value:      rValue.val,
+		CreatedAt:  unixTime(rValue.created),
+		LastAccess: unixTime(rValue.getLastAccess()),
+		ExpiresAt:  unixTime(rValue.getExpiry()),
+		Permanent:  rValue.permanent,
+	}, true
+}
+
+// TTL returns the remaining life of the input key, or NoExpiration
+// if the key never expires. It returns false if the key isn't
+// present or has expired.
+func (c *Cacher[C, T]) TTL(key C) (time.Duration, bool) {
+	now := c.now()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	rValue, ok := c.peekLocked(key, now)
+	if !ok {
+		return 0, false
+	}
+	expiry := rValue.getExpiry()
+	if expiry == 0 {
+		return NoExpiration, true
+	}
+	return time.Duration(expiry - now), true
+}
+
+// Touch renews the expiration time of the input key as a Get call
+// would do in revaluation mode, but without reading it. Keys which
+// never expire are left untouched. It returns false if the key
+// isn't present or has expired.
+func (c *Cacher[C, T]) Touch(key C) bool {
+	now := c.now()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	rValue, ok := c.peekLocked(key, now)
+	if !ok {
+		return false
+	}
+	if rValue.getExpiry() != 0 {
+		rValue.setExpiry(now + int64(rValue.ttl))
+	}
+	return true
+}
+
+// Persist makes the input key permanent, as if it was set via
+// SetPermanent, without rewriting its value. It returns false if
+// the key isn't present or has expired.
+func (c *Cacher[C, T]) Persist(key C) bool {
+	c.mutex.Lock()
+	defer c.unlock()
+	rValue, ok := c.peekLocked(key, c.now())
+	if !ok {
+		return false
+	}
+	persisted := rValue.clone()
+	persisted.permanent = true
+	persisted.expiry = 0
+	c.cacheMap[key] = persisted
+	return true
+}
+
+// Expire sets the TTL of the input key to d, counting from now,
+// and makes it non-permanent. The key is deleted right away if d
+// isn't positive. It returns false if the key isn't present or
+// has expired.
+func (c *Cacher[C, T]) Expire(key C, d time.Duration) bool {
+	now := c.now()
+	c.mutex.Lock()
+	defer c.unlock()
+	rValue, ok := c.peekLocked(key, now)
+	if !ok {
+		return false
+	}
+	if d <= 0 {
+		c.deleteLocked(key, RemovalExpired)
+		return true
+	}
+	expiring := rValue.clone()
+	expiring.permanent = false
+	expiring.ttl = d
+	expiring.expiry = now + int64(d)
+	c.cacheMap[key] = expiring
+	return true
+}
+
+// Peek is used to get value of a key without side effects, see
+// Cacher.Peek.
+func (s *ShardedCacher[K, V]) Peek(key K) (V, bool) {
+	return s.shard(key).Peek(key)
+}
+
+// GetEntry is used to get value of a key along with its metadata,
+// see Cacher.GetEntry.
+func (s *ShardedCacher[K, V]) GetEntry(key K) (Entry[V], bool) {
+	return s.shard(key).GetEntry(key)
+}
+
+// TTL returns the remaining life of a key, see Cacher.TTL.
+func (s *ShardedCacher[K, V]) TTL(key K) (time.Duration, bool) {
+	return s.shard(key).TTL(key)
+}
+
+// Touch renews the expiration time of a key, see Cacher.Touch.
+func (s *ShardedCacher[K, V]) Touch(key K) bool {
+	return s.shard(key).Touch(key)
+}
+
+// Persist makes a key permanent, see Cacher.Persist.
+func (s *ShardedCacher[K, V]) Persist(key K) bool {
+	return s.shard(key).Persist(key)
+}
+
+// Expire sets the TTL of a key, see Cacher.Expire.
+func (s *ShardedCacher[K, V]) Expire(key K, d time.Duration) bool {
+	return s.shard(key).Expire(key, d)
+}
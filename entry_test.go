@@ -0,0 +1,64 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacher_EntryMetadata(t *testing.T) {
+	start := time.Now()
+	clock := NewManualClock(start)
+	c := NewCacher[string, int](&NewCacherOpts{
+		TimeToLive: time.Minute,
+		Revaluate:  true,
+		Clock:      clock,
+	})
+	c.Set("a", 1)
+
+	e, ok := c.GetEntry("a")
+	if !ok || e.Value != 1 || !e.CreatedAt.Equal(start) || !e.LastAccess.IsZero() || e.Permanent {
+		t.Errorf("Cacher.GetEntry() = %+v, %v", e, ok)
+	}
+	if !e.ExpiresAt.Equal(start.Add(time.Minute)) {
+		t.Errorf("Cacher.GetEntry().ExpiresAt = %v, want %v", e.ExpiresAt, start.Add(time.Minute))
+	}
+
+	clock.Add(10 * time.Second)
+	// Peek never renews the key.
+	if v, ok := c.Peek("a"); !ok || v != 1 {
+		t.Errorf("Cacher.Peek() = %d, %v, want 1, true", v, ok)
+	}
+	if d, _ := c.TTL("a"); d != 50*time.Second {
+		t.Errorf("Cacher.TTL() after Peek = %v, want 50s", d)
+	}
+	c.Get("a")
+	if d, _ := c.TTL("a"); d != time.Minute {
+		t.Errorf("Cacher.TTL() after Get = %v, want 1m", d)
+	}
+	if e, _ := c.GetEntry("a"); !e.LastAccess.Equal(clock.Now()) {
+		t.Errorf("Cacher.GetEntry().LastAccess = %v, want %v", e.LastAccess, clock.Now())
+	}
+
+	clock.Add(30 * time.Second)
+	c.Touch("a")
+	if d, _ := c.TTL("a"); d != time.Minute {
+		t.Errorf("Cacher.TTL() after Touch = %v, want 1m", d)
+	}
+
+	c.Persist("a")
+	if d, _ := c.TTL("a"); d != NoExpiration {
+		t.Errorf("Cacher.TTL() after Persist = %v, want NoExpiration", d)
+	}
+	if e, _ := c.GetEntry("a"); !e.Permanent || !e.CreatedAt.Equal(start) {
+		t.Errorf("Cacher.GetEntry() after Persist = %+v", e)
+	}
+
+	c.Expire("a", time.Second)
+	clock.Add(time.Second)
+	if _, ok := c.Peek("a"); ok {
+		t.Errorf("key outlived the TTL set via Expire")
+	}
+	if _, ok := c.TTL("missing"); ok {
+		t.Errorf("Cacher.TTL() found a missing key")
+	}
+}
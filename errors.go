@@ -0,0 +1,21 @@
+package cacher
+
+import "errors"
+
+var (
+	// ErrNoLoader is returned by Cacher.GetOrLoad when neither the
+	// call nor the Cacher instance provides a loader.
+	ErrNoLoader = errors.New("cacher: no loader configured")
+	// ErrCacherClosed is returned by the operations of a Cacher
+	// which has been closed via Cacher.Close.
+	ErrCacherClosed = errors.New("cacher: cacher is closed")
+	// ErrKeyArity is returned by PolyKeyer.NewE when it gets a
+	// wrong number of extra keys.
+	ErrKeyArity = errors.New("cacher: invalid amount of extra keys")
+	// ErrInvalidKey is returned by PolyKeyer.Parse and Duplet.Parse
+	// when the key wasn't created by the keyer.
+	ErrInvalidKey = errors.New("cacher: invalid key")
+	// ErrNotFound is returned by Store.Load when the key isn't in
+	// the store.
+	ErrNotFound = errors.New("cacher: key not found")
+)
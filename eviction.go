@@ -1,54 +1,71 @@
 package cacher
 
-import "time"
-
-type EvictionPolicy interface {
-	getEvictableValue() evictibleValue
-}
-
-type _DefaultEviction struct {
-	revaluate bool
-	ttl       int64
-}
-
-func (d *_DefaultEviction) getEvictableValue() evictibleValue {
-	return &defaultEviction{
-		revaluate: d.revaluate,
-		ttl:       d.ttl,
-	}
+// EvictionPolicy decides which key should leave a size bounded
+// Cacher once it holds more than NewCacherOpts.MaxKeys keys.
+// Implement it and pass it via WithPolicy to plug in
+// a domain specific policy, eg. one that evicts muted chats first.
+//
+// The Cacher reports every change to its key set through the
+// hooks below:
+//
+// OnInsert is called when a new key is added to the cache, or
+// an existing key is overwritten.
+//
+// OnAccess is called when a key is successfully retrieved.
+//
+// OnDelete is called when a key leaves the cache for any reason,
+// including evictions requested via Victim.
+//
+// Victim is called while the cache holds more than MaxKeys keys
+// and must return the key to evict next, or false if it has no
+// key to offer. Returning the key which was just inserted rejects
+// its admission. Victim must only return keys that are present in
+// the cache, the Cacher stops evicting otherwise.
+//
+// OnInsert, OnDelete and Victim are called with the write lock of
+// the owning Cacher held, while OnAccess may be called under its
+// read lock from many goroutines at once, hence implementations
+// need their own synchronisation. Hooks must not call back into
+// the Cacher. A policy must not be shared between Cacher instances.
+type EvictionPolicy[K comparable] interface {
+	OnInsert(key K)
+	OnAccess(key K)
+	OnDelete(key K)
+	Victim() (key K, ok bool)
 }
 
-func DefaultEvictionPolicy(revaluate bool, ttl int64) EvictionPolicy {
-	return &_DefaultEviction{
-		revaluate: revaluate,
-		ttl:       ttl,
+// NewEvictionPolicy returns the built-in EvictionPolicy for the
+// input EvictionMode, maxKeys is the bound of the Cacher it will
+// be used with.
+func NewEvictionPolicy[K comparable](mode EvictionMode, maxKeys int) EvictionPolicy[K] {
+	switch mode {
+	case EvictionLFU:
+		return NewLFUPolicy[K]()
+	case EvictionTinyLFU:
+		return NewTinyLFUPolicy[K](maxKeys)
+	default:
+		return NewLRUPolicy[K]()
 	}
 }
 
-type evictibleValue interface {
-	isExpired(dry bool) bool
+// DefaultEviction holds the expiry settings which were returned by
+// DefaultEvictionPolicy before EvictionPolicy became generic.
+//
+// Deprecated: Expiry is configured via NewCacherOpts.TimeToLive
+// and NewCacherOpts.Revaluate, the Cacher doesn't use this type.
+type DefaultEviction struct {
+	Revaluate bool
+	// TTL is in seconds.
+	TTL int64
 }
 
-type defaultEviction struct {
-	expiry    int64
-	revaluate bool
-	ttl       int64
-}
-
-func (d *defaultEviction) isExpired(dry bool) bool {
-	if d.expiry == 0 {
-		return false
-	}
-	currTime := time.Now().Unix()
-	if d.expiry <= currTime {
-		return true
-	}
-	if dry {
-		return false
-	}
-	if !d.revaluate {
-		return false
-	}
-	(*d).expiry = currTime + d.ttl
-	return false
+// DefaultEvictionPolicy returns the input expiry settings, as
+// used by the Cacher before size based eviction was introduced.
+//
+// Deprecated: Use NewCacherOpts.TimeToLive and
+// NewCacherOpts.Revaluate for expiry, and NewCacherOpts.MaxKeys
+// with NewCacherOpts.Eviction or WithPolicy for eviction. It
+// will be removed in a future release.
+func DefaultEvictionPolicy(revaluate bool, ttl int64) *DefaultEviction {
+	return &DefaultEviction{Revaluate: revaluate, TTL: ttl}
 }
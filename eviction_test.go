@@ -0,0 +1,101 @@
+package cacher
+
+import (
+	"testing"
+)
+
+func TestCacher_MaxKeysLFU(t *testing.T) {
+	c := NewCacher[int, int](&NewCacherOpts{MaxKeys: 3, Eviction: EvictionLFU})
+	for i := 1; i <= 3; i++ {
+		c.Set(i, i)
+	}
+	// 1 and 3 are used more often than 2.
+	for i := 0; i < 3; i++ {
+		c.Get(1)
+		c.Get(3)
+	}
+	c.Set(4, 4)
+	if _, ok := c.Get(2); ok {
+		t.Errorf("Cacher.Get(2) hit, want it evicted")
+	}
+	for _, key := range []int{1, 3, 4} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("Cacher.Get(%d) missed, want hit", key)
+		}
+	}
+}
+
+func TestCacher_MaxKeysTinyLFUScanResistance(t *testing.T) {
+	const maxKeys = 100
+	c := NewCacher[int, int](&NewCacherOpts{MaxKeys: maxKeys, Eviction: EvictionTinyLFU})
+	// Warm up a set of hot keys.
+	for round := 0; round < 5; round++ {
+		for i := 0; i < maxKeys/2; i++ {
+			c.Set(i, i)
+			c.Get(i)
+		}
+	}
+	// Scan a lot of one-off keys.
+	for i := 1000; i < 1000+10*maxKeys; i++ {
+		c.Set(i, i)
+	}
+	if n := c.NumKeys(); n != maxKeys {
+		t.Errorf("Cacher.NumKeys() = %d, want %d", n, maxKeys)
+	}
+	var hits int
+	for i := 0; i < maxKeys/2; i++ {
+		if _, ok := c.Get(i); ok {
+			hits++
+		}
+	}
+	if hits < maxKeys/2*9/10 {
+		t.Errorf("only %d of %d hot keys survived the scan", hits, maxKeys/2)
+	}
+}
+
+// evenFirstPolicy is a custom EvictionPolicy which always evicts
+// the smallest even key, and falls back to the smallest odd one.
+type evenFirstPolicy struct {
+	keys map[int]struct{}
+}
+
+func (p *evenFirstPolicy) OnInsert(key int) { p.keys[key] = struct{}{} }
+func (p *evenFirstPolicy) OnAccess(int)     {}
+func (p *evenFirstPolicy) OnDelete(key int) { delete(p.keys, key) }
+
+func (p *evenFirstPolicy) Victim() (victim int, ok bool) {
+	for key := range p.keys {
+		switch {
+		case !ok,
+			key%2 == 0 && (victim%2 != 0 || key < victim),
+			key%2 != 0 && victim%2 != 0 && key < victim:
+			victim, ok = key, true
+		}
+	}
+	return
+}
+
+func TestCacher_CustomPolicy(t *testing.T) {
+	policy := &evenFirstPolicy{keys: make(map[int]struct{})}
+	c := NewCacher(&NewCacherOpts{MaxKeys: 3}, WithPolicy[int, int](policy))
+	for i := 1; i <= 5; i++ {
+		c.Set(i, i)
+	}
+	for _, key := range []int{2, 4} {
+		if _, ok := c.Get(key); ok {
+			t.Errorf("Cacher.Get(%d) hit, want it evicted", key)
+		}
+	}
+	if len(policy.keys) != c.NumKeys() {
+		t.Errorf("policy tracks %d keys, cacher holds %d", len(policy.keys), c.NumKeys())
+	}
+}
+
+func TestNewShardedCacher_Policy(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewShardedCacher didn't panic on a shared policy")
+		}
+	}()
+	NewShardedCacher(4, nil, &NewCacherOpts{MaxKeys: 4}, WithPolicy[int, int](NewLRUPolicy[int]()))
+}
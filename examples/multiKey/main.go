@@ -7,22 +7,25 @@ import (
 	"github.com/AnimeKaizoku/cacher"
 )
 
-// We'll be using PolyKeyer hence we need to choose string as the data type for Key.
+// chatKey is our composite key made of 2 values: a chat id and the kind of that chat.
+// Key2 can be used as the data type for Key directly, hence we don't need to format
+// the values into a string on every call.
+type chatKey = cacher.Key2[int64, string]
+
 // In this example, we won't be expiring keys and hence we don't pass them to opts.
-var cache = cacher.NewCacher[string, string](nil)
+var cache = cacher.NewCacher[chatKey, string](nil)
 
-// Following code initiates a PolyKeyer instance.
+// Following code initiates a PolyKeyer instance, we'll only use it to show our keys as
+// strings.
 // We've chosen "chat" as the prefix key (also called primary key) and 2 as our secondary
-// parameters.
-// Note that it'll panic in case you pass different number of secondary keys to the keyer.New
-// method than the chosen one while initiation.
+// parameters, as our keys are made of 2 values.
 var keyer = cacher.NewPolyKeyer("chat", 2)
 
 // This function will call Get method on input key and print it if found.
-func get(key string) {
+func get(key chatKey) {
 	title, ok := cache.Get(key)
 	if !ok {
-		fmt.Printf("Key '%s' not found in the valid cache!\n", key)
+		fmt.Printf("Key '%s' not found in the valid cache!\n", key.Format(keyer))
 		return
 	}
 	fmt.Println("Title of that chat is:", title)
@@ -38,16 +41,16 @@ func getAll() {
 }
 
 func main() {
-	// We create a new key with exactly 2 arguments (we chose while initialising polykeyer)
-	// Let's assume that our 1st argument will be chat id and 2nd argument can either be
+	// We create a new key out of 2 values.
+	// Let's assume that our 1st value will be chat id and 2nd value can either be
 	// "public" or "private".
-	var key = keyer.New("101", "private")
+	var key = cacher.NewKey2[int64](101, "private")
 	// Here we set the value for our key we created above and let our value be the title
 	// of chat.
 	cache.Set(key, "King's Chat")
 
-	// Uncomment the following line of code to see how does a poly key look like actually:
-	// fmt.Println("This is how a polykey look like:", key)
+	// Uncomment the following line of code to see how does our key look like as a poly key:
+	// fmt.Println("This is how a polykey look like:", key.Format(keyer))
 
 	// Let's retrieve our key
 	// We'll use the get function we wrote earlier in this example for it!
@@ -67,13 +70,13 @@ func main() {
 
 	// Let's add the key again and a few more for further tutorial.
 	cache.Set(key, "King's Chat")
-	cache.Set(keyer.New("102", "public"), "Bob's chitchat group")
-	cache.Set(keyer.New("103", "private"), "Cacher Test Chat")
+	cache.Set(cacher.NewKey2[int64](102, "public"), "Bob's chitchat group")
+	cache.Set(cacher.NewKey2[int64](103, "private"), "Cacher Test Chat")
 
-	key1 := keyer.New("104", "public")
+	key1 := cacher.NewKey2[int64](104, "public")
 	cache.Set(key1, "Github Public Chat")
 
-	cache.Set(keyer.New("105", "private"), "King Hero")
+	cache.Set(cacher.NewKey2[int64](105, "private"), "King Hero")
 
 	// Let's print the number of keys now:
 	fmt.Println("Number of keys:", cache.NumKeys())
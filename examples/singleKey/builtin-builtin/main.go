@@ -21,6 +21,7 @@ import (
 var cache = cacher.NewCacher[int64, string](&cacher.NewCacherOpts{
 	TimeToLive:    time.Second * 10,
 	CleanInterval: time.Minute * 2,
+	CleanerMode:   cacher.CleaningLocal,
 	Revaluate:     true,
 })
 
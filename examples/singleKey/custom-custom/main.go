@@ -44,6 +44,7 @@ type date struct{ date, month, year int }
 var cache = cacher.NewCacher[rank, []User](&cacher.NewCacherOpts{
 	TimeToLive:    time.Second * 10,
 	CleanInterval: time.Minute * 2,
+	CleanerMode:   cacher.CleaningLocal,
 	Revaluate:     true,
 })
 
@@ -0,0 +1,83 @@
+package cacher
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// keyHasher is implemented by the composite keys, eg. Key2, so
+// that they're hashed without going through fmt.
+type keyHasher interface {
+	hash() uint64
+}
+
+// hashKey returns a 64 bit hash of any comparable key.
+// Strings, integers, floats and composite keys are hashed directly
+// while other types fall back to hashing their fmt representation.
+func hashKey[C comparable](key C) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return hashString(k)
+	case int:
+		return mix64(uint64(k))
+	case int8:
+		return mix64(uint64(k))
+	case int16:
+		return mix64(uint64(k))
+	case int32:
+		return mix64(uint64(k))
+	case int64:
+		return mix64(uint64(k))
+	case uint:
+		return mix64(uint64(k))
+	case uint8:
+		return mix64(uint64(k))
+	case uint16:
+		return mix64(uint64(k))
+	case uint32:
+		return mix64(uint64(k))
+	case uint64:
+		return mix64(k)
+	case uintptr:
+		return mix64(uint64(k))
+	case float32:
+		return mix64(uint64(math.Float32bits(k)))
+	case float64:
+		return mix64(math.Float64bits(k))
+	case bool:
+		if k {
+			return mix64(1)
+		}
+		return mix64(0)
+	case keyHasher:
+		return k.hash()
+	default:
+		return hashString(fmt.Sprintf("%#v", k))
+	}
+}
+
+// hashString is the 64 bit FNV-1a hash of s.
+func hashString(s string) uint64 {
+	var h uint64 = fnvOffset64
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return h
+}
+
+// mix64 is the finaliser of splitmix64, it spreads the bits of
+// small integers over the whole word.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
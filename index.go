@@ -0,0 +1,105 @@
+package cacher
+
+import (
+	"math/rand"
+	"strings"
+)
+
+const (
+	indexMaxLevel = 24
+	// indexLevelUp is the inverse of the probability for a node to
+	// be promoted to the next level.
+	indexLevelUp = 4
+)
+
+// keyIndex is an ordered set of string keys, implemented as a skip
+// list. It lets the prefix operations of Cacher visit only the keys
+// which share a prefix instead of scanning the whole cache.
+//
+// It isn't safe for concurrent use, the Cacher guards it with its
+// own lock.
+type keyIndex struct {
+	head  indexNode
+	level int
+}
+
+type indexNode struct {
+	key  string
+	next []*indexNode
+}
+
+func newKeyIndex() *keyIndex {
+	return &keyIndex{
+		head:  indexNode{next: make([]*indexNode, indexMaxLevel)},
+		level: 1,
+	}
+}
+
+func randomIndexLevel() int {
+	level := 1
+	for level < indexMaxLevel && rand.Intn(indexLevelUp) == 0 {
+		level++
+	}
+	return level
+}
+
+// seek fills path with the last node before key at every level,
+// and returns the first node whose key is not less than key.
+func (idx *keyIndex) seek(key string, path *[indexMaxLevel]*indexNode) *indexNode {
+	n := &idx.head
+	for l := idx.level - 1; l >= 0; l-- {
+		for n.next[l] != nil && n.next[l].key < key {
+			n = n.next[l]
+		}
+		if path != nil {
+			path[l] = n
+		}
+	}
+	return n.next[0]
+}
+
+// insert adds the input key, it's a no-op if it's already present.
+func (idx *keyIndex) insert(key string) {
+	var path [indexMaxLevel]*indexNode
+	if n := idx.seek(key, &path); n != nil && n.key == key {
+		return
+	}
+	level := randomIndexLevel()
+	for l := idx.level; l < level; l++ {
+		path[l] = &idx.head
+	}
+	if level > idx.level {
+		idx.level = level
+	}
+	n := &indexNode{key: key, next: make([]*indexNode, level)}
+	for l := 0; l < level; l++ {
+		n.next[l] = path[l].next[l]
+		path[l].next[l] = n
+	}
+}
+
+// remove drops the input key, it's a no-op if it isn't present.
+func (idx *keyIndex) remove(key string) {
+	var path [indexMaxLevel]*indexNode
+	n := idx.seek(key, &path)
+	if n == nil || n.key != key {
+		return
+	}
+	for l := range n.next {
+		path[l].next[l] = n.next[l]
+	}
+	for idx.level > 1 && idx.head.next[idx.level-1] == nil {
+		idx.level--
+	}
+}
+
+// ascend calls fn for every key which starts with prefix, in
+// ascending order, until fn returns false. fn must not modify
+// the index.
+func (idx *keyIndex) ascend(prefix string, fn func(key string) bool) {
+	for n := idx.seek(prefix, nil); n != nil && strings.HasPrefix(n.key, prefix); n = n.next[0] {
+		if !fn(n.key) {
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package cacher
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestKeyIndex(t *testing.T) {
+	idx := newKeyIndex()
+	want := make(map[string]bool)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		key := fmt.Sprintf("chat.%d.%d", rng.Intn(50), rng.Intn(50))
+		if rng.Intn(3) == 0 {
+			idx.remove(key)
+			delete(want, key)
+		} else {
+			idx.insert(key)
+			want[key] = true
+		}
+	}
+	for _, prefix := range []string{"", "chat.", "chat.1", "chat.1.", "chat.49.49", "chat.99", "z"} {
+		var exp []string
+		for key := range want {
+			if strings.HasPrefix(key, prefix) {
+				exp = append(exp, key)
+			}
+		}
+		sort.Strings(exp)
+		var got []string
+		idx.ascend(prefix, func(key string) bool {
+			got = append(got, key)
+			return true
+		})
+		if strings.Join(got, ",") != strings.Join(exp, ",") {
+			t.Errorf("keyIndex.ascend(%q) = %d keys, want %d", prefix, len(got), len(exp))
+		}
+	}
+}
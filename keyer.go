@@ -5,7 +5,12 @@ import (
 	"strings"
 )
 
-const keySep = '.'
+const (
+	keySep = '.'
+	// keyEscape precedes the separators and escape characters which
+	// are part of a key, so that keys can be split back into parts.
+	keyEscape = '\\'
+)
 
 // PolyKeyer is a special type of struct which is used when
 // you want to set more than 1 parameter as a key to some value.
@@ -15,6 +20,10 @@ const keySep = '.'
 // 1st key and set while making a new PolyKeyer, while extraKeys
 // are provided while creating keys from a polykeyer.
 //
+// Separators and backslashes inside of the keys are escaped with
+// a backslash, hence New("a.b", "c") and New("a", "b.c") create
+// different keys, and Parse gives back the original keys.
+//
 // Note: You should use Duplet in case there is only one extra
 // parameter.
 type PolyKeyer struct {
@@ -29,32 +38,68 @@ type PolyKeyer struct {
 // this function will create a new PolyKeyer which would create
 // unified key of type string with 3 keys in it.
 func NewPolyKeyer(primaryKey string, numExtraKeys int) *PolyKeyer {
+	return NewPolyKeyerWithSep(primaryKey, numExtraKeys, keySep)
+}
+
+// NewPolyKeyerWithSep works like NewPolyKeyer, but the created
+// PolyKeyer uses the input separator instead of a dot (.).
+// It panics if the separator is a backslash, which is used for
+// escaping.
+func NewPolyKeyerWithSep(primaryKey string, numExtraKeys int, sep rune) *PolyKeyer {
+	checkKeySep("NewPolyKeyerWithSep", sep)
 	return &PolyKeyer{
 		primaryKey: primaryKey,
 		extraKeys:  numExtraKeys,
-		sep:        keySep,
+		sep:        sep,
 	}
 }
 
 // It creates a new unity key of type string with 1st key as
 // the primary key of current PolyKeyer and rest of the keys
 // in the same order as they were passed as an argument.
+// It panics if the number of extra keys doesn't match the one
+// of current PolyKeyer, see NewE.
 func (k *PolyKeyer) New(extraKeys ...string) string {
-	if len(extraKeys) == 0 || (len(extraKeys) != k.extraKeys) {
+	key, err := k.NewE(extraKeys...)
+	if err != nil {
 		panic(fmt.Sprintf("cacher.PolyKeyer.New: invalid amound of extra keys to PolyKeyer[%s]", k.primaryKey))
 	}
+	return key
+}
+
+// NewE works like New, but returns an error wrapping ErrKeyArity
+// instead of panicking if the number of extra keys doesn't match
+// the one of current PolyKeyer.
+func (k *PolyKeyer) NewE(extraKeys ...string) (string, error) {
+	if len(extraKeys) == 0 || (len(extraKeys) != k.extraKeys) {
+		return "", fmt.Errorf("%w: PolyKeyer[%s] takes %d, got %d", ErrKeyArity, k.primaryKey, k.extraKeys, len(extraKeys))
+	}
 	n := len(extraKeys) + len(k.primaryKey)
-	for i := 0; i < len(extraKeys); i++ {
-		n += len(fmt.Sprint(extraKeys[i]))
+	for _, s := range extraKeys {
+		n += len(s)
 	}
 	var b strings.Builder
 	b.Grow(n)
-	b.WriteString(k.primaryKey)
+	writeKeyPart(&b, k.primaryKey, k.sep)
 	for _, s := range extraKeys {
 		b.WriteRune(k.sep)
-		b.WriteString(s)
+		writeKeyPart(&b, s, k.sep)
 	}
-	return b.String()
+	return b.String(), nil
+}
+
+// Parse splits a key created by current PolyKeyer back into its
+// extra keys, it returns an error wrapping ErrInvalidKey if the
+// key wasn't created by current PolyKeyer.
+func (k *PolyKeyer) Parse(key string) ([]string, error) {
+	parts, err := splitKey(key, k.sep)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) != k.extraKeys+1 || parts[0] != k.primaryKey {
+		return nil, fmt.Errorf("%w: %q doesn't belong to PolyKeyer[%s]", ErrInvalidKey, key, k.primaryKey)
+	}
+	return parts[1:], nil
 }
 
 // Dupley is a special type of PolyKeyer which is used when
@@ -65,6 +110,9 @@ func (k *PolyKeyer) New(extraKeys ...string) string {
 // making a new Duplet, while secondaryKey is provided while
 // creating keys from a duplet.
 //
+// Separators and backslashes inside of the keys are escaped like
+// the ones of PolyKeyer.
+//
 // Note: You can use PolyKeyer in case you want to use more
 // than one extra parameter.
 type Duplet struct {
@@ -75,9 +123,18 @@ type Duplet struct {
 // This function creates a new Duplet instance with the
 // provided primary key.
 func NewDuplet(primaryKey string) *Duplet {
+	return NewDupletWithSep(primaryKey, keySep)
+}
+
+// NewDupletWithSep works like NewDuplet, but the created Duplet
+// uses the input separator instead of a dot (.).
+// It panics if the separator is a backslash, which is used for
+// escaping.
+func NewDupletWithSep(primaryKey string, sep rune) *Duplet {
+	checkKeySep("NewDupletWithSep", sep)
 	return &Duplet{
 		primaryKey: primaryKey,
-		sep:        keySep,
+		sep:        sep,
 	}
 }
 
@@ -85,5 +142,152 @@ func NewDuplet(primaryKey string) *Duplet {
 // the primary key of current Duplet and secondary key as the
 // one passed which was passed as an argument to this function.
 func (k *Duplet) New(key any) string {
-	return fmt.Sprintf("%s%c%v", k.primaryKey, k.sep, key)
+	s, ok := key.(string)
+	if !ok {
+		s = fmt.Sprint(key)
+	}
+	var b strings.Builder
+	b.Grow(len(k.primaryKey) + len(s) + 1)
+	writeKeyPart(&b, k.primaryKey, k.sep)
+	b.WriteRune(k.sep)
+	writeKeyPart(&b, s, k.sep)
+	return b.String()
+}
+
+// Parse splits a key created by current Duplet back into its
+// secondary key, formatted as a string. It returns an error
+// wrapping ErrInvalidKey if the key wasn't created by current
+// Duplet.
+func (k *Duplet) Parse(key string) ([]string, error) {
+	parts, err := splitKey(key, k.sep)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) != 2 || parts[0] != k.primaryKey {
+		return nil, fmt.Errorf("%w: %q doesn't belong to Duplet[%s]", ErrInvalidKey, key, k.primaryKey)
+	}
+	return parts[1:], nil
+}
+
+func checkKeySep(fn string, sep rune) {
+	if sep == keyEscape {
+		panic(fmt.Sprintf("cacher.%s: the separator can't be a backslash", fn))
+	}
+}
+
+// writeKeyPart writes a part of a key, escaping the separators
+// and escape characters in it.
+func writeKeyPart(b *strings.Builder, s string, sep rune) {
+	if !strings.ContainsRune(s, sep) && !strings.ContainsRune(s, keyEscape) {
+		b.WriteString(s)
+		return
+	}
+	for _, r := range s {
+		if r == sep || r == keyEscape {
+			b.WriteRune(keyEscape)
+		}
+		b.WriteRune(r)
+	}
+}
+
+// splitKey splits a key written with writeKeyPart into its
+// unescaped parts.
+func splitKey(key string, sep rune) ([]string, error) {
+	var parts []string
+	var b strings.Builder
+	escaped := false
+	for _, r := range key {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == keyEscape:
+			escaped = true
+		case r == sep:
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("%w: %q ends with an escape character", ErrInvalidKey, key)
+	}
+	return append(parts, b.String()), nil
+}
+
+// Wildcard matches any extra key when passed to the prefix
+// operations of Cacher, eg. Cacher.GetByPrefix.
+const Wildcard = "*"
+
+// Keyer is implemented by PolyKeyer and Duplet, it's used by the
+// prefix operations of Cacher to find the keys created by them.
+type Keyer interface {
+	// Prefix returns the longest prefix shared by all the keys
+	// whose first extra keys are the input ones.
+	Prefix(partialKeys ...string) string
+	// Match reports whether the input key was created by current
+	// keyer, and its first extra keys match the input pattern.
+	// Wildcard matches any extra key.
+	Match(key string, pattern ...string) bool
+}
+
+// Prefix returns the prefix shared by all the keys of current
+// PolyKeyer whose first extra keys are the input ones.
+// Eg: With "chat" as the primary key and 2 extra keys, Prefix("101")
+// returns "chat.101." which prefixes "chat.101.private".
+func (k *PolyKeyer) Prefix(partialKeys ...string) string {
+	var b strings.Builder
+	writeKeyPart(&b, k.primaryKey, k.sep)
+	b.WriteRune(k.sep)
+	for i, s := range partialKeys {
+		writeKeyPart(&b, s, k.sep)
+		if i < k.extraKeys-1 {
+			b.WriteRune(k.sep)
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether the input key was created by current
+// PolyKeyer and its first extra keys match the input pattern.
+// Eg: With "chat" as the primary key, Match(key, Wildcard, "private")
+// matches "chat.101.private" but not "chat.101.public".
+func (k *PolyKeyer) Match(key string, pattern ...string) bool {
+	if len(pattern) > k.extraKeys {
+		return false
+	}
+	parts, err := k.Parse(key)
+	return err == nil && matchKeyParts(parts, pattern)
+}
+
+// Prefix returns the prefix shared by all the keys of current
+// Duplet, or the key of the input secondary key if one is passed.
+func (k *Duplet) Prefix(partialKeys ...string) string {
+	if len(partialKeys) != 0 {
+		return k.New(partialKeys[0])
+	}
+	var b strings.Builder
+	writeKeyPart(&b, k.primaryKey, k.sep)
+	b.WriteRune(k.sep)
+	return b.String()
+}
+
+// Match reports whether the input key was created by current
+// Duplet and its secondary key matches the input pattern, if any.
+func (k *Duplet) Match(key string, pattern ...string) bool {
+	if len(pattern) > 1 {
+		return false
+	}
+	parts, err := k.Parse(key)
+	return err == nil && matchKeyParts(parts, pattern)
+}
+
+func matchKeyParts(parts, pattern []string) bool {
+	for i, p := range pattern {
+		if p != Wildcard && p != parts[i] {
+			return false
+		}
+	}
+	return true
 }
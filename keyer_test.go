@@ -1,6 +1,7 @@
 package cacher
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -117,3 +118,76 @@ func TestDuplet_New(t *testing.T) {
 		})
 	}
 }
+
+func TestPolyKeyer_NoCollisions(t *testing.T) {
+	keyer := NewPolyKeyer("user", 2)
+	a, b := keyer.New("a.b", "c"), keyer.New("a", "b.c")
+	if a == b {
+		t.Fatalf("PolyKeyer.New() collided on %q", a)
+	}
+	if c := keyer.New(`a\`, "b"); c == keyer.New("a", `\b`) {
+		t.Fatalf("PolyKeyer.New() collided on %q", c)
+	}
+}
+
+func TestPolyKeyer_Parse(t *testing.T) {
+	tests := []struct {
+		name   string
+		keyer  *PolyKeyer
+		parts  []string
+		wantTo string
+	}{
+		{"plain", NewPolyKeyer("chat", 2), []string{"101", "private"}, "chat.101.private"},
+		{"dotted", NewPolyKeyer("user", 2), []string{"john.doe", `c:\x`}, `user.john\.doe.c:\\x`},
+		{"empty", NewPolyKeyer("user", 2), []string{"", ""}, "user.."},
+		{"custom sep", NewPolyKeyerWithSep("chat", 2, ':'), []string{"a.b", "c:d"}, `chat:a.b:c\:d`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := tt.keyer.New(tt.parts...)
+			if key != tt.wantTo {
+				t.Errorf("PolyKeyer.New() = %q, want %q", key, tt.wantTo)
+			}
+			got, err := tt.keyer.Parse(key)
+			if err != nil {
+				t.Fatalf("PolyKeyer.Parse(%q) error = %v", key, err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.parts) || len(got) != len(tt.parts) {
+				t.Errorf("PolyKeyer.Parse(%q) = %q, want %q", key, got, tt.parts)
+			}
+		})
+	}
+}
+
+func TestPolyKeyer_ParseErrors(t *testing.T) {
+	keyer := NewPolyKeyer("chat", 2)
+	for _, key := range []string{"chat.101", "chat.101.private.x", "user.101.private", `chat.101.private\`} {
+		if _, err := keyer.Parse(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("PolyKeyer.Parse(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestPolyKeyer_NewE(t *testing.T) {
+	keyer := NewPolyKeyer("chat", 2)
+	if _, err := keyer.NewE("101"); !errors.Is(err, ErrKeyArity) {
+		t.Errorf("PolyKeyer.NewE() error = %v, want ErrKeyArity", err)
+	}
+	if key, err := keyer.NewE("101", "private"); err != nil || key != "chat.101.private" {
+		t.Errorf("PolyKeyer.NewE() = %q, %v, want chat.101.private, nil", key, err)
+	}
+}
+
+func TestDuplet_Parse(t *testing.T) {
+	keyer := NewDupletWithSep("user", '/')
+	key := keyer.New("a/b.c")
+	if key != `user/a\/b.c` {
+		t.Errorf("Duplet.New() = %q, want %q", key, `user/a\/b.c`)
+	}
+	if got, err := keyer.Parse(key); err != nil || len(got) != 1 || got[0] != "a/b.c" {
+		t.Errorf("Duplet.Parse(%q) = %q, %v, want [a/b.c], nil", key, got, err)
+	}
+	if _, err := keyer.Parse("user/a/b"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Duplet.Parse() error = %v, want ErrInvalidKey", err)
+	}
+}
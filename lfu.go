@@ -0,0 +1,102 @@
+package cacher
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lfuPolicy evicts the least frequently used key first, and the
+// least recently used one among keys sharing that frequency.
+// It keeps an ordered list of frequency buckets so that every
+// operation is O(1).
+type lfuPolicy[C comparable] struct {
+	mu      sync.Mutex
+	buckets *list.List
+	nodes   map[C]*lfuNode[C]
+}
+
+type lfuBucket[C comparable] struct {
+	freq uint64
+	keys *list.List
+}
+
+type lfuNode[C comparable] struct {
+	bucket *list.Element
+	elem   *list.Element
+}
+
+// NewLFUPolicy returns an EvictionPolicy which evicts the least
+// frequently used key first, ties are broken by recency.
+func NewLFUPolicy[C comparable]() EvictionPolicy[C] {
+	return &lfuPolicy[C]{
+		buckets: list.New(),
+		nodes:   make(map[C]*lfuNode[C]),
+	}
+}
+
+func (p *lfuPolicy[C]) OnInsert(key C) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n, ok := p.nodes[key]; ok {
+		p.increment(key, n)
+		return
+	}
+	front := p.buckets.Front()
+	if front == nil || front.Value.(*lfuBucket[C]).freq != 1 {
+		front = p.buckets.PushFront(&lfuBucket[C]{freq: 1, keys: list.New()})
+	}
+	p.nodes[key] = &lfuNode[C]{
+		bucket: front,
+		elem:   front.Value.(*lfuBucket[C]).keys.PushFront(key),
+	}
+}
+
+func (p *lfuPolicy[C]) OnAccess(key C) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n, ok := p.nodes[key]; ok {
+		p.increment(key, n)
+	}
+}
+
+// increment moves the node of key to the bucket with the next
+// frequency, creating it if it doesn't exist yet.
+func (p *lfuPolicy[C]) increment(key C, n *lfuNode[C]) {
+	curr := n.bucket.Value.(*lfuBucket[C])
+	next := n.bucket.Next()
+	if next == nil || next.Value.(*lfuBucket[C]).freq != curr.freq+1 {
+		next = p.buckets.InsertAfter(&lfuBucket[C]{freq: curr.freq + 1, keys: list.New()}, n.bucket)
+	}
+	p.unlink(n)
+	n.bucket = next
+	n.elem = next.Value.(*lfuBucket[C]).keys.PushFront(key)
+}
+
+// unlink removes the node from its bucket and drops the bucket
+// once it becomes empty.
+func (p *lfuPolicy[C]) unlink(n *lfuNode[C]) {
+	b := n.bucket.Value.(*lfuBucket[C])
+	b.keys.Remove(n.elem)
+	if b.keys.Len() == 0 {
+		p.buckets.Remove(n.bucket)
+	}
+}
+
+func (p *lfuPolicy[C]) OnDelete(key C) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n, ok := p.nodes[key]; ok {
+		p.unlink(n)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *lfuPolicy[C]) Victim() (key C, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	front := p.buckets.Front()
+	if front == nil {
+		return
+	}
+	return front.Value.(*lfuBucket[C]).keys.Back().Value.(C), true
+}
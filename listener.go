@@ -0,0 +1,60 @@
+package cacher
+
+// RemovalFunc is a listener which is called whenever a key leaves
+// the cache, with the last value of the key and the reason of its
+// removal.
+type RemovalFunc[C comparable, T any] func(key C, val T, reason RemovalReason)
+
+type removal[C comparable, T any] struct {
+	key    C
+	val    T
+	reason RemovalReason
+}
+
+func (c *Cacher[C, T]) hasListeners() bool {
+	return c.onEvict != nil || c.onRemove != nil
+}
+
+// recordLocked queues a removal for the listeners, they're fired
+// once the write lock is released via unlock. Caller must hold
+// the write lock.
+func (c *Cacher[C, T]) recordLocked(key C, val T, reason RemovalReason) {
+	if !c.hasListeners() {
+		return
+	}
+	c.removed = append(c.removed, removal[C, T]{key, val, reason})
+}
+
+// unlock releases the write lock and then fires the listeners for
+// the keys removed while it was held, so that they may safely call
+// back into the Cacher.
+func (c *Cacher[C, T]) unlock() {
+	removed := c.removed
+	c.removed = nil
+	writes := c.writes
+	c.writes = nil
+	var seq uint64
+	if len(writes) != 0 {
+		// Ticketed before releasing the lock, so that the writes reach
+		// the Store in the order of the changes without holding the
+		// lock during the Store calls.
+		seq = c.writeSeq
+		c.writeSeq++
+	}
+	c.mutex.Unlock()
+	if len(writes) != 0 {
+		c.store.write(seq, writes)
+	}
+	c.notify(removed)
+}
+
+func (c *Cacher[C, T]) notify(removed []removal[C, T]) {
+	for _, r := range removed {
+		if c.onEvict != nil && r.reason.IsEviction() {
+			c.onEvict(r.key, r.val, r.reason)
+		}
+		if c.onRemove != nil {
+			c.onRemove(r.key, r.val, r.reason)
+		}
+	}
+}
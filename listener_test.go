@@ -0,0 +1,57 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacher_RemovalListeners(t *testing.T) {
+	var removed, evicted []RemovalReason
+	var c *Cacher[int, string]
+	clock := NewManualClock(time.Now())
+	c = NewCacher(&NewCacherOpts{MaxKeys: 2, Clock: clock},
+		WithOnRemove(func(key int, val string, reason RemovalReason) {
+			removed = append(removed, reason)
+			// Listeners run outside the lock and may use the cache.
+			c.NumKeys()
+		}),
+		WithOnEvict(func(key int, val string, reason RemovalReason) {
+			evicted = append(evicted, reason)
+		}),
+	)
+	c.Set(1, "a")
+	c.Set(1, "b")
+	c.Set(2, "c")
+	c.Set(3, "d")
+	c.Delete(3)
+	c.DeleteSome(func(v string) bool { return v == "c" })
+	c.SetWithTTL(4, "e", time.Second)
+	c.Reset()
+	c.SetWithTTL(5, "f", time.Second)
+	clock.Add(time.Second)
+	c.Get(5)
+
+	wantRemoved := []RemovalReason{
+		RemovalReplaced, RemovalCapacity, RemovalDeleted,
+		RemovalDeleted, RemovalReset, RemovalExpired,
+	}
+	wantEvicted := []RemovalReason{RemovalCapacity, RemovalExpired}
+	if !equalReasons(removed, wantRemoved) {
+		t.Errorf("OnRemove reasons = %v, want %v", removed, wantRemoved)
+	}
+	if !equalReasons(evicted, wantEvicted) {
+		t.Errorf("OnEvict reasons = %v, want %v", evicted, wantEvicted)
+	}
+}
+
+func equalReasons(a, b []RemovalReason) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,187 @@
+package cacher
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LoaderFunc loads the value of a key which is missing from
+// the cache, eg. by querying a database.
+type LoaderFunc[C comparable, T any] func(key C) (T, error)
+
+// GetOrLoad is used to get value of the input key, loading it
+// with the input loader if the key is not found or has expired.
+// If loader is nil, the loader set via WithLoader is
+// used instead.
+//
+// A successfully loaded value is stored with the default TTL of
+// current Cacher instance, errors are returned as they are and
+// nothing gets cached.
+//
+// Concurrent calls missing the same key share a single call to
+// the loader, and all of them receive its value or error. If the
+// key is set or deleted while it's being loaded, the loaded value
+// is still returned but it doesn't overwrite the change.
+func (c *Cacher[C, T]) GetOrLoad(key C, loader LoaderFunc[C, T]) (T, error) {
+	if val, ok := c.Get(key); ok {
+		return val, nil
+	}
+	if c.Closed() {
+		var zero T
+		return zero, ErrCacherClosed
+	}
+	if loader == nil {
+		loader = c.loader
+	}
+	if loader == nil {
+		var zero T
+		return zero, ErrNoLoader
+	}
+	return c.loads.do(key, func() (T, error) {
+		now := c.now()
+		c.mutex.Lock()
+		if rValue, ok := c.cacheMap[key]; ok && !rValue.isExpired(now) {
+			// Set since we missed it.
+			c.mutex.Unlock()
+			return rValue.val, nil
+		}
+		p := c.beginLoadLocked(key)
+		c.mutex.Unlock()
+
+		start := time.Now()
+		var val T
+		var err error
+		loaded := false
+		defer func() {
+			c.mutex.Lock()
+			defer c.unlock()
+			// Don't overwrite a value which was set or deleted while
+			// we were loading.
+			if c.endLoadLocked(key, p) || !loaded || err != nil {
+				return
+			}
+			v := c.packValue(val, nil, false)
+			v.loadTime = time.Since(start)
+			v.synced = true
+			c.setLocked(key, v)
+		}()
+		val, err = loader(key)
+		loaded = true
+		return val, err
+	})
+}
+
+// pendingLoad tracks the loads of a key which are in progress, so
+// that their values don't overwrite the changes made meanwhile.
+type pendingLoad struct {
+	refs  int
+	stale bool
+}
+
+// beginLoadLocked registers a load of the input key, it must be
+// paired with a call to endLoadLocked. Caller must hold the write
+// lock.
+func (c *Cacher[C, T]) beginLoadLocked(key C) *pendingLoad {
+	if c.pending == nil {
+		c.pending = make(map[C]*pendingLoad)
+	}
+	p, ok := c.pending[key]
+	if !ok {
+		p = new(pendingLoad)
+		c.pending[key] = p
+	}
+	p.refs++
+	return p
+}
+
+// endLoadLocked unregisters a load of the input key, and reports
+// whether the key was set or deleted since it began. Caller must
+// hold the write lock.
+func (c *Cacher[C, T]) endLoadLocked(key C, p *pendingLoad) bool {
+	p.refs--
+	if p.refs == 0 {
+		delete(c.pending, key)
+	}
+	return p.stale
+}
+
+// invalidateLoadLocked marks the loads of the input key which are
+// in progress as stale, caller must hold the write lock.
+func (c *Cacher[C, T]) invalidateLoadLocked(key C) {
+	if p, ok := c.pending[key]; ok {
+		p.stale = true
+	}
+}
+
+// flightGroup deduplicates concurrent loads of the same key.
+type flightGroup[C comparable, T any] struct {
+	mu    sync.Mutex
+	calls map[C]*flight[T]
+}
+
+type flight[T any] struct {
+	wg  sync.WaitGroup
+	val T
+	err error
+}
+
+func newFlightGroup[C comparable, T any]() *flightGroup[C, T] {
+	return &flightGroup[C, T]{
+		calls: make(map[C]*flight[T]),
+	}
+}
+
+// do calls fn for the input key unless a call for the same key
+// is already in flight, in which case it waits for that call and
+// returns its results.
+func (g *flightGroup[C, T]) do(key C, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		f.wg.Wait()
+		return f.val, f.err
+	}
+	f := new(flight[T])
+	f.wg.Add(1)
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			f.err = fmt.Errorf("cacher: loader panicked: %v", r)
+			g.finish(key, f)
+			panic(r)
+		}
+		g.finish(key, f)
+	}()
+	f.val, f.err = fn()
+	return f.val, f.err
+}
+
+// goDo calls fn for the input key in a new goroutine unless a
+// call for the same key is already in flight, it doesn't wait for
+// the call to finish.
+func (g *flightGroup[C, T]) goDo(key C, fn func() (T, error)) {
+	g.mu.Lock()
+	if _, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		return
+	}
+	f := new(flight[T])
+	f.wg.Add(1)
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	go func() {
+		defer g.finish(key, f)
+		f.val, f.err = fn()
+	}()
+}
+
+func (g *flightGroup[C, T]) finish(key C, f *flight[T]) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	f.wg.Done()
+}
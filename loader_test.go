@@ -0,0 +1,91 @@
+package cacher
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacher_GetOrLoadSingleflight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	c := NewCacher(nil, WithLoader(func(key int) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "loaded", nil
+	}))
+	const callers = 50
+	var wg sync.WaitGroup
+	results := make([]string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.GetOrLoad(1, nil)
+		}(i)
+	}
+	// Give every caller a chance to join the flight.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+	for i, res := range results {
+		if res != "loaded" {
+			t.Errorf("caller %d got %q, want %q", i, res, "loaded")
+		}
+	}
+	if val, ok := c.Get(1); !ok || val != "loaded" {
+		t.Errorf("Cacher.Get(1) = %q, %v, want loaded value cached", val, ok)
+	}
+}
+
+func TestCacher_GetOrLoadError(t *testing.T) {
+	errLoad := errors.New("backend down")
+	c := NewCacher[int, string](nil)
+	if _, err := c.GetOrLoad(1, nil); err != ErrNoLoader {
+		t.Errorf("Cacher.GetOrLoad() error = %v, want %v", err, ErrNoLoader)
+	}
+	_, err := c.GetOrLoad(1, func(int) (string, error) { return "", errLoad })
+	if err != errLoad {
+		t.Errorf("Cacher.GetOrLoad() error = %v, want %v", err, errLoad)
+	}
+	if c.NumKeys() != 0 {
+		t.Errorf("failed load was cached")
+	}
+}
+
+func TestCacher_GetOrLoadConcurrentWrite(t *testing.T) {
+	for _, write := range []string{"set", "delete"} {
+		loading := make(chan struct{})
+		release := make(chan struct{})
+		c := NewCacher(nil, WithLoader(func(key int) (string, error) {
+			close(loading)
+			<-release
+			return "stale", nil
+		}))
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.GetOrLoad(1, nil)
+		}()
+		<-loading
+		if write == "set" {
+			c.Set(1, "fresh")
+		} else {
+			c.Delete(1)
+		}
+		close(release)
+		<-done
+		val, ok := c.Get(1)
+		if write == "set" && (!ok || val != "fresh") {
+			t.Errorf("Cacher.Get(1) = %q, %v after a set during the load, want fresh, true", val, ok)
+		}
+		if write == "delete" && ok {
+			t.Errorf("Cacher.Get(1) = %q after a delete during the load, want a miss", val)
+		}
+	}
+}
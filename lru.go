@@ -0,0 +1,60 @@
+package cacher
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruPolicy evicts the least recently used key first.
+// Every operation is O(1).
+type lruPolicy[C comparable] struct {
+	mu    sync.Mutex
+	order *list.List
+	nodes map[C]*list.Element
+}
+
+// NewLRUPolicy returns an EvictionPolicy which evicts the least
+// recently used key first.
+func NewLRUPolicy[C comparable]() EvictionPolicy[C] {
+	return &lruPolicy[C]{
+		order: list.New(),
+		nodes: make(map[C]*list.Element),
+	}
+}
+
+func (p *lruPolicy[C]) OnInsert(key C) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e, ok := p.nodes[key]; ok {
+		p.order.MoveToFront(e)
+		return
+	}
+	p.nodes[key] = p.order.PushFront(key)
+}
+
+func (p *lruPolicy[C]) OnAccess(key C) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e, ok := p.nodes[key]; ok {
+		p.order.MoveToFront(e)
+	}
+}
+
+func (p *lruPolicy[C]) OnDelete(key C) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e, ok := p.nodes[key]; ok {
+		p.order.Remove(e)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *lruPolicy[C]) Victim() (key C, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e := p.order.Back()
+	if e == nil {
+		return
+	}
+	return e.Value.(C), true
+}
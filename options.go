@@ -0,0 +1,78 @@
+package cacher
+
+// Option sets a setting of a Cacher which depends on its key and
+// value types, eg. its loader, see NewCacher. Unlike the fields of
+// NewCacherOpts, these settings are checked against the types of
+// the Cacher at compile time.
+type Option[K comparable, V any] func(o *typedOpts[K, V])
+
+// typedOpts holds the settings set via Option.
+type typedOpts[K comparable, V any] struct {
+	policy   EvictionPolicy[K]
+	loader   LoaderFunc[K, V]
+	onEvict  RemovalFunc[K, V]
+	onRemove RemovalFunc[K, V]
+	store    Store[K, V]
+}
+
+func newTypedOpts[K comparable, V any](options []Option[K, V]) *typedOpts[K, V] {
+	o := new(typedOpts[K, V])
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+// WithPolicy sets a custom eviction policy which overrides
+// NewCacherOpts.Eviction. It isn't supported by NewShardedCacher,
+// since a policy can't be shared between shards.
+//
+// The value type can't be inferred, eg.
+// WithPolicy[int, string](NewLFUPolicy[int]()).
+func WithPolicy[K comparable, V any](policy EvictionPolicy[K]) Option[K, V] {
+	return func(o *typedOpts[K, V]) { o.policy = policy }
+}
+
+// WithLoader sets the default loader used by Cacher.GetOrLoad to
+// fetch keys which are missing from the cache, and by the
+// background refreshes of NewCacherOpts.RefreshAfterWrite and
+// NewCacherOpts.EarlyRefreshBeta.
+func WithLoader[K comparable, V any](loader LoaderFunc[K, V]) Option[K, V] {
+	return func(o *typedOpts[K, V]) { o.loader = loader }
+}
+
+// WithOnEvict sets a listener called whenever the Cacher removes
+// a key by itself, ie. when the key expires or is evicted due to
+// NewCacherOpts.MaxKeys.
+//
+// Listeners are called after the internal lock is released, hence
+// they may call back into the Cacher.
+func WithOnEvict[K comparable, V any](fn RemovalFunc[K, V]) Option[K, V] {
+	return func(o *typedOpts[K, V]) { o.onEvict = fn }
+}
+
+// WithOnRemove sets a listener called whenever a key leaves the
+// cache for any reason, including Delete, DeleteSome, Reset and
+// overwriting Set calls, see WithOnEvict.
+func WithOnRemove[K comparable, V any](fn RemovalFunc[K, V]) Option[K, V] {
+	return func(o *typedOpts[K, V]) { o.onRemove = fn }
+}
+
+// WithStore puts the Cacher in front of a backing store, eg. a
+// database. The Store is the default loader of GetOrLoad when no
+// loader is set (read-through), and GetOrLoadMany loads the keys
+// it misses with one Store.LoadMany call. The values set via Set
+// and the other setters are saved to the Store and the keys
+// deleted via Delete, DeleteMany, DeleteSome and the like are
+// deleted from it (write-through). Loaded values aren't written
+// back, and expired, evicted and reset keys stay in the Store.
+// Write-through happens right after the change, outside of the
+// lock and in the order of the changes, errors are reported to
+// NewCacherOpts.OnStoreError since setters don't return errors.
+//
+// See NewCacherOpts.WriteBehind to write changes asynchronously.
+// The types can't be inferred from a concrete store, eg.
+// WithStore[string, int](NewMemoryStore[string, int]()).
+func WithStore[K comparable, V any](store Store[K, V]) Option[K, V] {
+	return func(o *typedOpts[K, V]) { o.store = store }
+}
@@ -0,0 +1,93 @@
+package cacher
+
+// GetByPrefix returns the unexpired key-value pairs of current
+// Cacher instance whose keys were created by the input keyer and
+// start with the input extra keys, eg. with a PolyKeyer for
+// "chat" with 2 extra keys, GetByPrefix(keyer, "101") returns the
+// pairs of "chat.101.public" and "chat.101.private".
+//
+// Wildcard can be passed in place of any extra key, eg.
+// GetByPrefix(keyer, Wildcard, "private") matches "chat.*.private".
+//
+// Only the keys sharing the literal prefix before the first
+// Wildcard are visited if NewCacherOpts.PrefixIndex is set, the
+// whole cache is scanned otherwise.
+//
+// Note: It doesn't renew expiration time of any key
+// even if the revaluation mode is turned on for the
+// current Cacher instance.
+func (c *Cacher[C, T]) GetByPrefix(keyer Keyer, partialKeys ...string) map[C]T {
+	res := make(map[C]T)
+	now := c.now()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	c.matchLocked(keyer, partialKeys, func(key C, val *value[T]) {
+		if !val.isExpired(now) {
+			res[key] = val.getWithoutExpiry()
+		}
+	})
+	return res
+}
+
+// DeleteByPrefix deletes the keys of current Cacher instance which
+// were created by the input keyer and start with the input extra
+// keys, see GetByPrefix. Unlike GetByPrefix, it matches expired
+// keys too.
+func (c *Cacher[C, T]) DeleteByPrefix(keyer Keyer, partialKeys ...string) {
+	c.mutex.Lock()
+	defer c.unlock()
+	var keys []C
+	c.matchLocked(keyer, partialKeys, func(key C, _ *value[T]) {
+		keys = append(keys, key)
+	})
+	for _, key := range keys {
+		c.deleteLocked(key, RemovalDeleted)
+	}
+}
+
+// matchLocked calls fn for every key matching the input pattern,
+// caller must hold the lock. fn must not modify the cache.
+func (c *Cacher[C, T]) matchLocked(keyer Keyer, pattern []string, fn func(key C, val *value[T])) {
+	if c.index == nil {
+		for key, val := range c.cacheMap {
+			if s, ok := any(key).(string); ok && keyer.Match(s, pattern...) {
+				fn(key, val)
+			}
+		}
+		return
+	}
+	literal := pattern
+	for i, p := range pattern {
+		if p == Wildcard {
+			literal = pattern[:i]
+			break
+		}
+	}
+	c.index.ascend(keyer.Prefix(literal...), func(s string) bool {
+		if keyer.Match(s, pattern...) {
+			key := any(s).(C)
+			fn(key, c.cacheMap[key])
+		}
+		return true
+	})
+}
+
+// GetByPrefix returns the matching pairs of all the shards, see
+// Cacher.GetByPrefix.
+func (s *ShardedCacher[K, V]) GetByPrefix(keyer Keyer, partialKeys ...string) map[K]V {
+	res := make(map[K]V)
+	for _, c := range s.shards {
+		for k, v := range c.GetByPrefix(keyer, partialKeys...) {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// DeleteByPrefix deletes the matching keys of all the shards, see
+// Cacher.DeleteByPrefix.
+func (s *ShardedCacher[K, V]) DeleteByPrefix(keyer Keyer, partialKeys ...string) {
+	for _, c := range s.shards {
+		c.DeleteByPrefix(keyer, partialKeys...)
+	}
+}
@@ -0,0 +1,110 @@
+package cacher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacher_ByPrefix(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		c := NewCacher[string, int](&NewCacherOpts{PrefixIndex: indexed})
+		chats := NewPolyKeyer("chat", 2)
+		users := NewDuplet("user")
+		c.Set(chats.New("101", "public"), 1)
+		c.Set(chats.New("101", "private"), 2)
+		c.Set(chats.New("1010", "private"), 3)
+		c.Set(chats.New("102", "private"), 4)
+		c.Set(users.New(101), 5)
+		c.Set("chatter", 6)
+
+		for _, tt := range []struct {
+			pattern []string
+			want    int
+		}{
+			{nil, 4},
+			{[]string{"101"}, 2},
+			{[]string{"101", "private"}, 1},
+			{[]string{Wildcard, "private"}, 3},
+			{[]string{Wildcard, Wildcard}, 4},
+			{[]string{"103"}, 0},
+			{[]string{"101", "private", "extra"}, 0},
+		} {
+			if got := c.GetByPrefix(chats, tt.pattern...); len(got) != tt.want {
+				t.Errorf("indexed %v: Cacher.GetByPrefix(%q) = %v, want %d pairs", indexed, tt.pattern, got, tt.want)
+			}
+		}
+		if got := c.GetByPrefix(users); len(got) != 1 || got["user.101"] != 5 {
+			t.Errorf("indexed %v: Cacher.GetByPrefix(duplet) = %v, want map[user.101:5]", indexed, got)
+		}
+
+		c.DeleteByPrefix(chats, "101")
+		if n := c.NumKeys(); n != 4 {
+			t.Errorf("indexed %v: Cacher.NumKeys() after DeleteByPrefix = %d, want 4", indexed, n)
+		}
+		if _, ok := c.Get(chats.New("1010", "private")); !ok {
+			t.Errorf("indexed %v: DeleteByPrefix deleted a key of another chat", indexed)
+		}
+		c.Reset()
+		if got := c.GetByPrefix(chats); len(got) != 0 {
+			t.Errorf("indexed %v: Cacher.GetByPrefix() after Reset = %v", indexed, got)
+		}
+	}
+}
+
+func TestShardedCacher_ByPrefix(t *testing.T) {
+	s := NewShardedCacher[string, int](4, nil, &NewCacherOpts{PrefixIndex: true})
+	chats := NewPolyKeyer("chat", 2)
+	for _, kind := range []string{"public", "private", "linked"} {
+		s.Set(chats.New("101", kind), 1)
+		s.Set(chats.New("102", kind), 2)
+	}
+	if got := s.GetByPrefix(chats, "101"); len(got) != 3 {
+		t.Errorf("ShardedCacher.GetByPrefix() = %v, want 3 pairs", got)
+	}
+	s.DeleteByPrefix(chats, "101")
+	if n := s.NumKeys(); n != 3 {
+		t.Errorf("ShardedCacher.NumKeys() after DeleteByPrefix = %d, want 3", n)
+	}
+}
+
+func TestCacher_DeleteByPrefixExpired(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		clock := NewManualClock(time.Now())
+		store := NewMemoryStore[string, int]()
+		c := NewCacher(&NewCacherOpts{PrefixIndex: indexed, Clock: clock}, WithStore[string, int](store))
+		chats := NewPolyKeyer("chat", 2)
+		c.SetWithTTL(chats.New("101", "public"), 1, time.Minute)
+		c.Set(chats.New("101", "private"), 2)
+		clock.Add(time.Minute)
+
+		c.DeleteByPrefix(chats, "101")
+		if n := c.NumKeys(); n != 0 {
+			t.Errorf("indexed %v: Cacher.NumKeys() after DeleteByPrefix = %d, want 0", indexed, n)
+		}
+		if n := store.Len(); n != 0 {
+			t.Errorf("indexed %v: store has %d keys after DeleteByPrefix, want 0", indexed, n)
+		}
+	}
+}
+
+func TestNewCacher_PrefixIndexNeedsStringKeys(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewCacher() didn't panic for a PrefixIndex with int keys")
+		}
+	}()
+	NewCacher[int, int](&NewCacherOpts{PrefixIndex: true})
+}
+
+func TestCacher_ByPrefixEscaped(t *testing.T) {
+	c := NewCacher[string, int](&NewCacherOpts{PrefixIndex: true})
+	users := NewPolyKeyer("user", 2)
+	c.Set(users.New("john.doe", "mute"), 1)
+	c.Set(users.New("john", "doe.mute"), 2)
+	if got := c.GetByPrefix(users, "john"); len(got) != 1 {
+		t.Errorf("Cacher.GetByPrefix() = %v, want only the pair of john", got)
+	}
+	if got := c.GetByPrefix(users, Wildcard, "mute"); len(got) != 1 {
+		t.Errorf("Cacher.GetByPrefix() = %v, want only the pair of john.doe", got)
+	}
+}
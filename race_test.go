@@ -0,0 +1,123 @@
+package cacher
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// The following tests hammer a Cacher from many goroutines at once,
+// they're meant to be run with the race detector: go test -race
+
+func TestCacher_ConcurrentRevaluate(t *testing.T) {
+	c := NewCacher[int, int](&NewCacherOpts{
+		TimeToLive: time.Minute,
+		Revaluate:  true,
+	})
+	const keys = 64
+	for i := 0; i < keys; i++ {
+		c.Set(i, i)
+	}
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	worker := func(fn func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+					fn(i % keys)
+				}
+			}
+		}()
+	}
+	for w := 0; w < 4; w++ {
+		worker(func(i int) { c.Get(i) })
+	}
+	worker(func(i int) { c.Set(i, i) })
+	worker(func(i int) { c.SetWithTTL(i, i, time.Second) })
+	worker(func(int) { c.cleanExpired() })
+	worker(func(i int) { c.Delete(i) })
+	worker(func(int) { c.Keys() })
+	time.Sleep(200 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+}
+
+func TestCacher_ConcurrentRevaluateBounded(t *testing.T) {
+	for _, mode := range []EvictionMode{EvictionLRU, EvictionLFU, EvictionTinyLFU} {
+		c := NewCacher[int, int](&NewCacherOpts{
+			TimeToLive: time.Minute,
+			Revaluate:  true,
+			MaxKeys:    16,
+			Eviction:   mode,
+		})
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 2000; i++ {
+					key := (w*7 + i) % 64
+					if i%3 == 0 {
+						c.Set(key, i)
+					} else {
+						c.Get(key)
+					}
+					if i%100 == 0 {
+						c.cleanExpired()
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		if n := c.NumKeys(); n > 16 {
+			t.Errorf("mode %d: Cacher.NumKeys() = %d, want at most 16", mode, n)
+		}
+	}
+}
+
+func TestCacher_RevaluateRenewsWithEntryTTL(t *testing.T) {
+	// The cacher has no default TTL, renewing with it would expire
+	// the key right away.
+	c := NewCacher[int, int](&NewCacherOpts{Revaluate: true})
+	c.SetWithTTL(1, 1, time.Hour)
+	for i := 0; i < 3; i++ {
+		if _, ok := c.Get(1); !ok {
+			t.Fatalf("Cacher.Get(1) missed on call %d", i)
+		}
+	}
+}
+
+func TestCacher_ConcurrentIncrementalClean(t *testing.T) {
+	for _, opts := range []NewCacherOpts{
+		{CleanBatchSize: 8},
+		{CleanSampleSize: 8},
+	} {
+		opts.TimeToLive = time.Millisecond
+		c := newCacher[int, int](&opts)
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 2000; i++ {
+					key := (w*7 + i) % 256
+					switch i % 50 {
+					case 0:
+						c.cleanExpired()
+					case 1:
+						c.Reset()
+					default:
+						c.Set(key, i)
+						c.Get(key)
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+	}
+}
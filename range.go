@@ -0,0 +1,89 @@
+package cacher
+
+// Range calls fn for every unexpired key-value pair present in
+// the current Cacher instance, in no particular order. Iteration
+// stops as soon as fn returns false.
+//
+// Range copies the pairs while holding the read lock of the
+// Cacher and calls fn once it's released, hence fn may call any
+// method of the Cacher, eg. to delete the keys. Changes made
+// during the iteration aren't seen by it.
+//
+// Note: It doesn't renew expiration time of any key
+// even if the revaluation mode is turned on for the
+// current Cacher instance.
+func (c *Cacher[C, T]) Range(fn func(key C, val T) bool) {
+	now := c.now()
+	c.mutex.RLock()
+	keys := make([]C, 0, len(c.cacheMap))
+	vals := make([]T, 0, len(c.cacheMap))
+	for key, val := range c.cacheMap {
+		if !val.isExpired(now) {
+			keys = append(keys, key)
+			vals = append(vals, val.val)
+		}
+	}
+	c.mutex.RUnlock()
+	for i, key := range keys {
+		if !fn(key, vals[i]) {
+			return
+		}
+	}
+}
+
+// Keys returns the unexpired keys present in the current Cacher
+// instance, in no particular order.
+func (c *Cacher[C, T]) Keys() []C {
+	res := make([]C, 0, c.NumKeys())
+	c.Range(func(key C, _ T) bool {
+		res = append(res, key)
+		return true
+	})
+	return res
+}
+
+// Items returns the unexpired key-value pairs present in the
+// current Cacher instance as a map.
+func (c *Cacher[C, T]) Items() map[C]T {
+	res := make(map[C]T, c.NumKeys())
+	c.Range(func(key C, val T) bool {
+		res[key] = val
+		return true
+	})
+	return res
+}
+
+// Range calls fn for every unexpired key-value pair of every
+// shard, see Cacher.Range. The shards are copied one at a time.
+func (s *ShardedCacher[K, V]) Range(fn func(key K, val V) bool) {
+	stopped := false
+	for _, c := range s.shards {
+		c.Range(func(key K, val V) bool {
+			stopped = !fn(key, val)
+			return !stopped
+		})
+		if stopped {
+			return
+		}
+	}
+}
+
+// Keys returns the unexpired keys of every shard, see Cacher.Keys.
+func (s *ShardedCacher[K, V]) Keys() []K {
+	res := make([]K, 0, s.NumKeys())
+	for _, c := range s.shards {
+		res = append(res, c.Keys()...)
+	}
+	return res
+}
+
+// Items returns the unexpired key-value pairs of every shard as
+// a map, see Cacher.Items.
+func (s *ShardedCacher[K, V]) Items() map[K]V {
+	res := make(map[K]V, s.NumKeys())
+	s.Range(func(key K, val V) bool {
+		res[key] = val
+		return true
+	})
+	return res
+}
@@ -0,0 +1,39 @@
+//go:build go1.23
+
+package cacher
+
+import "iter"
+
+// All returns an iterator over the unexpired key-value pairs of
+// the current Cacher instance, see Cacher.Range. The loop body may
+// call any method of the Cacher.
+func (c *Cacher[C, T]) All() iter.Seq2[C, T] {
+	return c.Range
+}
+
+// KeysSeq returns an iterator over the unexpired keys of the
+// current Cacher instance, see Cacher.Range. The loop body may call
+// any method of the Cacher.
+func (c *Cacher[C, T]) KeysSeq() iter.Seq[C] {
+	return func(yield func(C) bool) {
+		c.Range(func(key C, _ T) bool {
+			return yield(key)
+		})
+	}
+}
+
+// All returns an iterator over the unexpired key-value pairs of
+// every shard, see ShardedCacher.Range.
+func (s *ShardedCacher[K, V]) All() iter.Seq2[K, V] {
+	return s.Range
+}
+
+// KeysSeq returns an iterator over the unexpired keys of every
+// shard, see ShardedCacher.Range.
+func (s *ShardedCacher[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.Range(func(key K, _ V) bool {
+			return yield(key)
+		})
+	}
+}
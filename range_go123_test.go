@@ -0,0 +1,49 @@
+//go:build go1.23
+
+package cacher
+
+import (
+	"testing"
+)
+
+func TestCacher_All(t *testing.T) {
+	c := NewCacher[int, int](nil)
+	for i := 0; i < 10; i++ {
+		c.Set(i, i*i)
+	}
+	var n int
+	for key, val := range c.All() {
+		if val != key*key {
+			t.Errorf("All() yielded %d: %d, want %d", key, val, key*key)
+		}
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	if n != 5 {
+		t.Errorf("All() yielded %d pairs before break, want 5", n)
+	}
+	n = 0
+	for range c.KeysSeq() {
+		n++
+	}
+	if n != 10 {
+		t.Errorf("KeysSeq() yielded %d keys, want 10", n)
+	}
+}
+
+func TestCacher_KeysSeqDelete(t *testing.T) {
+	c := NewCacher[int, int](nil)
+	for i := 0; i < 10; i++ {
+		c.Set(i, i)
+	}
+	for key := range c.KeysSeq() {
+		if _, ok := c.Get(key); ok {
+			c.Delete(key)
+		}
+	}
+	if n := c.NumKeys(); n != 0 {
+		t.Errorf("Cacher.NumKeys() = %d after deleting in KeysSeq(), want 0", n)
+	}
+}
@@ -0,0 +1,59 @@
+package cacher
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCacher_RangeSkipsExpired(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := NewCacher[int, string](&NewCacherOpts{Clock: clock})
+	c.Set(1, "one")
+	c.Set(2, "two")
+	c.SetWithTTL(3, "three", time.Second)
+	clock.Add(time.Second)
+
+	keys := c.Keys()
+	sort.Ints(keys)
+	if len(keys) != 2 || keys[0] != 1 || keys[1] != 2 {
+		t.Errorf("Cacher.Keys() = %v, want [1 2]", keys)
+	}
+	items := c.Items()
+	if len(items) != 2 || items[1] != "one" || items[2] != "two" {
+		t.Errorf("Cacher.Items() = %v, want map[1:one 2:two]", items)
+	}
+	var n int
+	c.Range(func(int, string) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Cacher.Range() called fn %d times after it returned false, want 1", n)
+	}
+}
+
+func TestCacher_RangeCallsBack(t *testing.T) {
+	c := NewCacher[int, int](nil)
+	for i := 0; i < 10; i++ {
+		c.Set(i, i)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Range(func(key, _ int) bool {
+			c.Get(key)
+			c.Set(key+100, key)
+			c.Delete(key)
+			return true
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Cacher.Range() deadlocked when fn called back into the Cacher")
+	}
+	if n := c.NumKeys(); n != 10 {
+		t.Errorf("Cacher.NumKeys() = %d, want the 10 keys set in fn", n)
+	}
+}
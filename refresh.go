@@ -0,0 +1,71 @@
+package cacher
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// maybeRefresh reloads the input key in the background if its
+// value is due for a refresh, see shouldRefresh. Only one reload
+// per key is in flight at any time.
+func (c *Cacher[C, T]) maybeRefresh(key C, rValue *value[T], now int64) {
+	if c.loader == nil || !c.shouldRefresh(rValue, now) {
+		return
+	}
+	loader := c.loader
+	c.loads.goDo(key, func() (T, error) {
+		start := time.Now()
+		val, err := loader(key)
+		if err != nil {
+			// Keep serving the current value until it expires.
+			return val, err
+		}
+		v := c.packValue(val, nil, rValue.permanent)
+		v.loadTime = time.Since(start)
+		v.synced = true
+		c.mutex.Lock()
+		defer c.unlock()
+		// Don't overwrite a value which was set or deleted while
+		// we were loading.
+		if c.cacheMap[key] == rValue {
+			c.setLocked(key, v)
+		}
+		return val, nil
+	})
+}
+
+// shouldRefresh reports whether a value is older than
+// NewCacherOpts.RefreshAfterWrite, or has been picked for an early
+// refresh by the XFetch algorithm.
+//
+// XFetch refreshes a value at time now with a probability which
+// grows as now gets closer to its expiry, and with the time it
+// took to load the value:
+//
+//	now - loadTime * beta * ln(rand()) >= expiry
+//
+// so that keys written at once don't all expire at once.
+func (c *Cacher[C, T]) shouldRefresh(rValue *value[T], now int64) bool {
+	if c.refreshAfter > 0 && now-rValue.created >= int64(c.refreshAfter) {
+		return true
+	}
+	if c.earlyRefreshBeta <= 0 || rValue.loadTime <= 0 {
+		return false
+	}
+	expiry := rValue.getExpiry()
+	if expiry == 0 {
+		return false
+	}
+	gap := -float64(rValue.loadTime) * c.earlyRefreshBeta * math.Log(rand.Float64())
+	return float64(now)+gap >= float64(expiry)
+}
+
+// jitter shortens the input TTL by a random fraction of up to
+// NewCacherOpts.TTLJitter.
+func (c *Cacher[C, T]) jitter(ttl time.Duration) time.Duration {
+	if c.ttlJitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+	return ttl - time.Duration(float64(ttl)*c.ttlJitter*rand.Float64())
+}
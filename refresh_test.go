@@ -0,0 +1,107 @@
+package cacher
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacher_RefreshAfterWrite(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	var version int32
+	release := make(chan struct{})
+	opts := &NewCacherOpts{
+		TimeToLive:        time.Minute,
+		RefreshAfterWrite: 10 * time.Second,
+		Clock:             clock,
+	}
+	c := NewCacher(opts, WithLoader(func(string) (int32, error) {
+		<-release
+		return atomic.AddInt32(&version, 1), nil
+	}))
+	c.Set("title", 0)
+
+	clock.Add(10 * time.Second)
+	// Stale reads return right away and share one reload.
+	for i := 0; i < 10; i++ {
+		if v, ok := c.Get("title"); !ok || v != 0 {
+			t.Fatalf("Cacher.Get() = %d, %v while refreshing, want 0, true", v, ok)
+		}
+	}
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if v, _ := c.Peek("title"); v == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("value wasn't refreshed in the background")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&version); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+	if d, _ := c.TTL("title"); d != time.Minute {
+		t.Errorf("Cacher.TTL() of the refreshed key = %v, want 1m", d)
+	}
+}
+
+func TestCacher_TTLJitter(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := NewCacher[int, int](&NewCacherOpts{
+		TimeToLive: time.Hour,
+		TTLJitter:  0.1,
+		Clock:      clock,
+	})
+	distinct := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+		d, _ := c.TTL(i)
+		if d > time.Hour || d < 54*time.Minute {
+			t.Fatalf("Cacher.TTL() = %v, want between 54m and 1h", d)
+		}
+		distinct[d] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("TTLJitter didn't spread the expiry of keys")
+	}
+	c.SetPermanent(-1, 0)
+	if d, _ := c.TTL(-1); d != NoExpiration {
+		t.Errorf("Cacher.TTL() of a permanent key = %v, want NoExpiration", d)
+	}
+}
+
+func TestCacher_EarlyRefresh(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	var loads int32
+	opts := &NewCacherOpts{
+		TimeToLive:       time.Minute,
+		EarlyRefreshBeta: 1,
+		Clock:            clock,
+	}
+	c := NewCacher(opts, WithLoader(func(string) (int32, error) {
+		time.Sleep(time.Millisecond)
+		return atomic.AddInt32(&loads, 1), nil
+	}))
+	if _, err := c.GetOrLoad("title", nil); err != nil {
+		t.Fatalf("Cacher.GetOrLoad() error = %v", err)
+	}
+	// Far from the expiry, the chance of a refresh is negligible.
+	for i := 0; i < 100; i++ {
+		c.Get("title")
+	}
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Fatalf("loader called %d times far from the expiry, want 1", n)
+	}
+	// Right before the expiry, a refresh is (almost) certain.
+	clock.Add(time.Minute - time.Nanosecond)
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&loads) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("value wasn't refreshed early")
+		}
+		c.Get("title")
+		time.Sleep(time.Millisecond)
+	}
+}
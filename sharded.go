@@ -0,0 +1,237 @@
+package cacher
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultShards is the number of shards used by NewShardedCacher
+// when it's asked for a non-positive amount of them.
+const DefaultShards = 16
+
+// ShardedCacher is a Cacher partitioned into several independently
+// locked shards, a key always lives in the shard picked by hashing
+// it. Use it instead of a Cacher for write heavy workloads, where
+// a single lock would serialise all the goroutines.
+//
+// It has the same API as Cacher, and its cleaner proceeds shard by
+// shard so that a cleaning pass never blocks the whole cache.
+type ShardedCacher[K comparable, V any] struct {
+	shards        []*Cacher[K, V]
+	hash          func(K) uint64
+	cleanInterval time.Duration
+	cleanerMode   CleaningMode
+	closeOnce     sync.Once
+	stop          chan struct{}
+}
+
+// NewShardedCacher is a generic function which creates a new
+// ShardedCacher instance with the input number of shards.
+//
+// hasher is used to pick the shard of a key, if it's nil then a
+// built-in hash is used which handles strings and numbers directly
+// and falls back to hashing the fmt representation of other types.
+//
+// opts and options are the same as the ones of NewCacher and apply
+// to every shard, except for MaxKeys which is split evenly between
+// them. WithPolicy is not supported since a policy can't be shared
+// between shards, NewShardedCacher panics if it's passed.
+func NewShardedCacher[KeyT comparable, ValueT any](shards int, hasher func(KeyT) uint64, opts *NewCacherOpts, options ...Option[KeyT, ValueT]) *ShardedCacher[KeyT, ValueT] {
+	if opts == nil {
+		opts = new(NewCacherOpts)
+	}
+	if newTypedOpts(options).policy != nil {
+		panic("cacher.NewShardedCacher: WithPolicy can't be shared between shards, use NewCacherOpts.Eviction instead")
+	}
+	if shards <= 0 {
+		shards = DefaultShards
+	}
+	if hasher == nil {
+		hasher = hashKey[KeyT]
+	}
+	shardOpts := *opts
+	if opts.MaxKeys > 0 {
+		shardOpts.MaxKeys = (opts.MaxKeys + shards - 1) / shards
+	}
+	s := ShardedCacher[KeyT, ValueT]{
+		shards: make([]*Cacher[KeyT, ValueT], shards),
+		hash:   hasher,
+	}
+	for i := range s.shards {
+		s.shards[i] = newCacher(&shardOpts, options...)
+	}
+	s.cleanInterval = s.shards[0].cleanInterval
+	s.cleanerMode = s.shards[0].cleanerMode
+	switch s.cleanerMode {
+	case CleaningNone:
+	case CleaningCentral:
+		centralCleaner.Register(&s)
+	default:
+		s.stop = make(chan struct{})
+		go s.cleaner()
+	}
+	return &s
+}
+
+// shardIndex returns the index of the shard holding the input key.
+func (s *ShardedCacher[K, V]) shardIndex(key K) int {
+	return int(s.hash(key) % uint64(len(s.shards)))
+}
+
+func (s *ShardedCacher[K, V]) shard(key K) *Cacher[K, V] {
+	return s.shards[s.shardIndex(key)]
+}
+
+// Set is used to set a new key-value pair to the current
+// ShardedCacher instance. It doesn't return anything.
+func (s *ShardedCacher[K, V]) Set(key K, val V) {
+	s.shard(key).Set(key, val)
+}
+
+// SetWithTTL is used to set a new key-value pair with a specific
+// TTL, see Cacher.SetWithTTL.
+func (s *ShardedCacher[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+	s.shard(key).SetWithTTL(key, val, ttl)
+}
+
+// SetPermanent is used to set a new key-value pair which never
+// expires, see Cacher.SetPermanent.
+func (s *ShardedCacher[K, V]) SetPermanent(key K, val V) {
+	s.shard(key).SetPermanent(key, val)
+}
+
+// Get is used to get value of the input key, see Cacher.Get.
+func (s *ShardedCacher[K, V]) Get(key K) (V, bool) {
+	return s.shard(key).Get(key)
+}
+
+// Lookup is used to get value of the input key telling apart
+// missing and expired keys, see Cacher.Lookup.
+func (s *ShardedCacher[K, V]) Lookup(key K) (V, LookupStatus) {
+	return s.shard(key).Lookup(key)
+}
+
+// GetOrLoad is used to get value of the input key, loading it if
+// it's missing, see Cacher.GetOrLoad.
+func (s *ShardedCacher[K, V]) GetOrLoad(key K, loader LoaderFunc[K, V]) (V, error) {
+	return s.shard(key).GetOrLoad(key, loader)
+}
+
+// GetAll is used to return the values of all the shards,
+// see Cacher.GetAll.
+func (s *ShardedCacher[K, V]) GetAll() []V {
+	var res []V
+	for _, c := range s.shards {
+		res = append(res, c.GetAll()...)
+	}
+	return res
+}
+
+// GetSome is used to get values of all the shards which satisfy
+// the condition determined via SegrigatorFunc, see Cacher.GetSome.
+func (s *ShardedCacher[K, V]) GetSome(cond SegrigatorFunc[V]) []V {
+	res := []V{}
+	for _, c := range s.shards {
+		res = append(res, c.GetSome(cond)...)
+	}
+	return res
+}
+
+// GetSomeEntries is used to get key-value pairs of all the
+// shards which satisfy the condition determined via
+// EntrySegrigatorFunc, see Cacher.GetSomeEntries.
+func (s *ShardedCacher[K, V]) GetSomeEntries(cond EntrySegrigatorFunc[K, V]) map[K]V {
+	res := make(map[K]V)
+	for _, c := range s.shards {
+		for k, v := range c.GetSomeEntries(cond) {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// Delete is used to delete the input key, see Cacher.Delete.
+func (s *ShardedCacher[K, V]) Delete(key K) {
+	s.shard(key).Delete(key)
+}
+
+// DeleteSome is used to delete keys of all the shards which
+// satisfy the condition determined via SegrigatorFunc, see
+// Cacher.DeleteSome.
+func (s *ShardedCacher[K, V]) DeleteSome(cond SegrigatorFunc[V]) {
+	for _, c := range s.shards {
+		c.DeleteSome(cond)
+	}
+}
+
+// DeleteSomeEntries is used to delete key-value pairs of all the
+// shards which satisfy the condition determined via
+// EntrySegrigatorFunc, see Cacher.DeleteSomeEntries.
+func (s *ShardedCacher[K, V]) DeleteSomeEntries(cond EntrySegrigatorFunc[K, V]) {
+	for _, c := range s.shards {
+		c.DeleteSomeEntries(cond)
+	}
+}
+
+// Reset deletes all keys of every shard, see Cacher.Reset.
+func (s *ShardedCacher[K, V]) Reset() {
+	for _, c := range s.shards {
+		c.Reset()
+	}
+}
+
+// NumKeys counts the number of keys present in all the shards
+// and returns that count.
+func (s *ShardedCacher[K, V]) NumKeys() int {
+	var n int
+	for _, c := range s.shards {
+		n += c.NumKeys()
+	}
+	return n
+}
+
+// Close stops the cleaner of current ShardedCacher instance and
+// closes all of its shards, see Cacher.Close. It returns the first
+// error returned by the shards.
+// Calling Close again returns ErrCacherClosed.
+func (s *ShardedCacher[K, V]) Close() error {
+	err := ErrCacherClosed
+	s.closeOnce.Do(func() {
+		err = nil
+		if s.cleanerMode == CleaningCentral {
+			centralCleaner.Unregister(s)
+		} else if s.stop != nil {
+			close(s.stop)
+		}
+		for _, c := range s.shards {
+			if cerr := c.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	})
+	return err
+}
+
+// Closed reports whether Close was called on current
+// ShardedCacher instance.
+func (s *ShardedCacher[K, V]) Closed() bool {
+	return s.shards[0].Closed()
+}
+
+func (s *ShardedCacher[K, V]) getCleanInterval() time.Duration {
+	return s.cleanInterval
+}
+
+// cleanExpired cleans one shard at a time, so only the shard
+// being cleaned is locked at any moment.
+func (s *ShardedCacher[K, V]) cleanExpired() {
+	for _, c := range s.shards {
+		c.cleanExpired()
+	}
+}
+
+// A function used by current ShardedCacher instance to clean
+// expired keys on regular basis.
+func (s *ShardedCacher[K, V]) cleaner() {
+	runCleaner(s, s.stop)
+}
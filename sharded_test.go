@@ -0,0 +1,59 @@
+package cacher
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestShardedCacher_Concurrent(t *testing.T) {
+	s := NewShardedCacher[int, int](8, nil, nil)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := w*1000 + i
+				s.Set(key, key)
+				if v, ok := s.Get(key); !ok || v != key {
+					t.Errorf("ShardedCacher.Get(%d) = %d, %v", key, v, ok)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if n := s.NumKeys(); n != 8000 {
+		t.Errorf("ShardedCacher.NumKeys() = %d, want 8000", n)
+	}
+	s.DeleteSome(func(v int) bool { return v%2 == 0 })
+	if n := len(s.GetAll()); n != 4000 {
+		t.Errorf("len(ShardedCacher.GetAll()) = %d, want 4000", n)
+	}
+	s.Reset()
+	if n := s.NumKeys(); n != 0 {
+		t.Errorf("ShardedCacher.NumKeys() after Reset = %d, want 0", n)
+	}
+}
+
+func TestShardedCacher_CustomHasher(t *testing.T) {
+	// Every key lands on the first shard, which holds at most
+	// ceil(10/4) keys.
+	s := NewShardedCacher[string, int](4, func(string) uint64 { return 0 }, &NewCacherOpts{MaxKeys: 10})
+	for _, key := range []string{"a", "b", "c", "d"} {
+		s.Set(key, 0)
+	}
+	if n := s.shards[0].NumKeys(); n != 3 {
+		t.Errorf("first shard holds %d keys, want 3", n)
+	}
+	if n := s.NumKeys(); n != 3 {
+		t.Errorf("ShardedCacher.NumKeys() = %d, want 3", n)
+	}
+}
+
+func TestShardedCacher_CloseError(t *testing.T) {
+	s := NewShardedCacher[int, int](4, nil, nil)
+	s.shards[1].Close()
+	if err := s.Close(); err != ErrCacherClosed {
+		t.Errorf("ShardedCacher.Close() error = %v, want the %v of the closed shard", err, ErrCacherClosed)
+	}
+}
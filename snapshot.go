@@ -0,0 +1,208 @@
+package cacher
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Encoder writes values to a stream, *gob.Encoder and
+// *json.Encoder implement it.
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder reads values from a stream, *gob.Decoder and
+// *json.Decoder implement it.
+type Decoder interface {
+	Decode(v any) error
+}
+
+// Codec creates the encoders and decoders used to write and read
+// snapshots of a Cacher, see Cacher.SaveTo and Cacher.LoadFrom.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
+func (gobCodec) NewDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder { return json.NewEncoder(w) }
+func (jsonCodec) NewDecoder(r io.Reader) Decoder { return json.NewDecoder(r) }
+
+var (
+	// GobCodec encodes snapshots with encoding/gob, keys and values
+	// must satisfy its requirements, eg. have exported fields.
+	// It's the default codec of every Cacher.
+	GobCodec Codec = gobCodec{}
+	// JSONCodec encodes snapshots with encoding/json, it's slower
+	// than GobCodec but produces human readable snapshots.
+	JSONCodec Codec = jsonCodec{}
+)
+
+const snapshotVersion = 1
+
+type snapshotHeader struct {
+	Version int
+	Count   int
+}
+
+// snapshotEntry is the encoded form of a key-value pair, a zero
+// ExpiresAt means that the key had no expiry. TTL is the duration
+// the expiry is renewed by in revaluation mode, it's missing from
+// the snapshots written before it was added.
+type snapshotEntry[C comparable, T any] struct {
+	Key       C
+	Value     T
+	ExpiresAt time.Time
+	Permanent bool
+	TTL       time.Duration
+}
+
+// SaveTo writes a snapshot of all the unexpired key-value pairs
+// of current Cacher instance to the input writer, along with their
+// expiry time and permanent flag. It uses the codec set via
+// NewCacherOpts.Codec, or GobCodec by default.
+//
+// The cache is only locked while the pairs are being collected,
+// not while they're being written.
+func (c *Cacher[C, T]) SaveTo(w io.Writer) error {
+	return writeSnapshot(c.codec, w, c.snapshot())
+}
+
+// LoadFrom restores a snapshot written by SaveTo from the input
+// reader. Keys which expired since the snapshot was taken are
+// dropped, and restored keys keep their TTL but follow the
+// revaluation setting of current Cacher instance from then on.
+// Restored keys overwrite existing ones, and nothing is restored
+// if the snapshot can't be read completely. Restored keys aren't
+// written to the Store, see WithStore.
+func (c *Cacher[C, T]) LoadFrom(r io.Reader) error {
+	entries, err := readSnapshot[C, T](c.codec, r)
+	if err != nil {
+		return err
+	}
+	return c.restore(entries)
+}
+
+func (c *Cacher[C, T]) snapshot() []snapshotEntry[C, T] {
+	now := c.now()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	entries := make([]snapshotEntry[C, T], 0, len(c.cacheMap))
+	for key, val := range c.cacheMap {
+		if val.isExpired(now) {
+			continue
+		}
+		e := snapshotEntry[C, T]{
+			Key:       key,
+			Value:     val.val,
+			Permanent: val.permanent,
+			TTL:       val.ttl,
+		}
+		if expiry := val.getExpiry(); expiry != 0 {
+			e.ExpiresAt = time.Unix(0, expiry)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func (c *Cacher[C, T]) restore(entries []snapshotEntry[C, T]) error {
+	now := c.clock.Now()
+	c.mutex.Lock()
+	defer c.unlock()
+	if c.status == cacherDeleted {
+		return ErrCacherClosed
+	}
+	for _, e := range entries {
+		if !e.ExpiresAt.IsZero() && !e.ExpiresAt.After(now) {
+			continue
+		}
+		ttl := e.TTL
+		if ttl == 0 && !e.ExpiresAt.IsZero() {
+			// Written before the TTL was saved.
+			ttl = e.ExpiresAt.Sub(now)
+		}
+		val := c.packValue(e.Value, &ttl, e.Permanent)
+		if !e.ExpiresAt.IsZero() {
+			val.setExpiry(e.ExpiresAt.UnixNano())
+		}
+		val.synced = true
+		c.setLocked(e.Key, val)
+	}
+	return nil
+}
+
+func writeSnapshot[C comparable, T any](codec Codec, w io.Writer, entries []snapshotEntry[C, T]) error {
+	enc := codec.NewEncoder(w)
+	if err := enc.Encode(snapshotHeader{Version: snapshotVersion, Count: len(entries)}); err != nil {
+		return fmt.Errorf("cacher: failed to write snapshot header: %w", err)
+	}
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("cacher: failed to write snapshot entry: %w", err)
+		}
+	}
+	return nil
+}
+
+func readSnapshot[C comparable, T any](codec Codec, r io.Reader) ([]snapshotEntry[C, T], error) {
+	dec := codec.NewDecoder(r)
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("cacher: failed to read snapshot header: %w", err)
+	}
+	if header.Version != snapshotVersion {
+		return nil, fmt.Errorf("cacher: unsupported snapshot version %d", header.Version)
+	}
+	// Don't trust the header for preallocation, it may be corrupt.
+	var entries []snapshotEntry[C, T]
+	for i := 0; i < header.Count; i++ {
+		var e snapshotEntry[C, T]
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("cacher: failed to read snapshot entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// SaveTo writes a snapshot of all the shards of current
+// ShardedCacher instance to the input writer, see Cacher.SaveTo.
+// Each shard is only locked while its pairs are being collected.
+func (s *ShardedCacher[K, V]) SaveTo(w io.Writer) error {
+	var entries []snapshotEntry[K, V]
+	for _, c := range s.shards {
+		entries = append(entries, c.snapshot()...)
+	}
+	return writeSnapshot(s.shards[0].codec, w, entries)
+}
+
+// LoadFrom restores a snapshot written by SaveTo, see
+// Cacher.LoadFrom. Snapshots are interchangeable between Cacher
+// and ShardedCacher instances.
+func (s *ShardedCacher[K, V]) LoadFrom(r io.Reader) error {
+	entries, err := readSnapshot[K, V](s.shards[0].codec, r)
+	if err != nil {
+		return err
+	}
+	parts := make([][]snapshotEntry[K, V], len(s.shards))
+	for _, e := range entries {
+		i := s.shardIndex(e.Key)
+		parts[i] = append(parts[i], e)
+	}
+	for i, c := range s.shards {
+		if err := c.restore(parts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
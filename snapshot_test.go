@@ -0,0 +1,96 @@
+package cacher
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestCacher_SaveToLoadFrom(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		codec Codec
+	}{
+		{"gob", GobCodec},
+		{"json", JSONCodec},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &NewCacherOpts{TimeToLive: time.Hour, Codec: tt.codec}
+			src := NewCacher[string, int](opts)
+			src.Set("ttl", 1)
+			src.SetPermanent("permanent", 2)
+
+			var buf bytes.Buffer
+			if err := src.SaveTo(&buf); err != nil {
+				t.Fatalf("Cacher.SaveTo() error = %v", err)
+			}
+			dst := NewCacher[string, int](opts)
+			if err := dst.LoadFrom(&buf); err != nil {
+				t.Fatalf("Cacher.LoadFrom() error = %v", err)
+			}
+			for key, want := range map[string]int{"ttl": 1, "permanent": 2} {
+				if got, ok := dst.Get(key); !ok || got != want {
+					t.Errorf("Cacher.Get(%q) = %d, %v, want %d, true", key, got, ok, want)
+				}
+			}
+			dst.mutex.RLock()
+			if v := dst.cacheMap["permanent"]; !v.permanent || v.expiry != 0 {
+				t.Errorf("permanent key restored with expiry %d", v.expiry)
+			}
+			if v := dst.cacheMap["ttl"]; v.expiry == 0 {
+				t.Errorf("ttl key restored without expiry")
+			}
+			dst.mutex.RUnlock()
+		})
+	}
+}
+
+func TestCacher_LoadFromDropsExpired(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeSnapshot(GobCodec, &buf, []snapshotEntry[int, int]{
+		{Key: 1, Value: 1, ExpiresAt: time.Now().Add(-time.Minute)},
+		{Key: 2, Value: 2, ExpiresAt: time.Now().Add(time.Minute)},
+	})
+	if err != nil {
+		t.Fatalf("writeSnapshot() error = %v", err)
+	}
+	c := NewCacher[int, int](nil)
+	if err := c.LoadFrom(&buf); err != nil {
+		t.Fatalf("Cacher.LoadFrom() error = %v", err)
+	}
+	if _, ok := c.Get(1); ok {
+		t.Errorf("key expired while saved was restored")
+	}
+	if _, ok := c.Get(2); !ok {
+		t.Errorf("unexpired key wasn't restored")
+	}
+}
+
+func TestCacher_LoadFromKeepsTTL(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	src := NewCacher[string, int](&NewCacherOpts{Clock: clock})
+	src.SetWithTTL("a", 1, 2*time.Hour)
+	var buf bytes.Buffer
+	if err := src.SaveTo(&buf); err != nil {
+		t.Fatalf("Cacher.SaveTo() error = %v", err)
+	}
+
+	store := newRecordingStore()
+	dst := NewCacher(&NewCacherOpts{Clock: clock, Revaluate: true}, WithStore[string, int](store))
+	if err := dst.LoadFrom(&buf); err != nil {
+		t.Fatalf("Cacher.LoadFrom() error = %v", err)
+	}
+	if n := store.numSaves(); n != 0 {
+		t.Errorf("restored keys were written to the store %d times", n)
+	}
+	clock.Add(time.Hour)
+	// The renewal must use the saved TTL rather than the zero
+	// TimeToLive of dst.
+	if _, ok := dst.Get("a"); !ok {
+		t.Fatalf("Cacher.Get(a) missed an hour before its expiry")
+	}
+	clock.Add(time.Hour + time.Minute)
+	if _, ok := dst.Get("a"); !ok {
+		t.Errorf("Cacher.Get(a) missed, want its expiry renewed by 2h")
+	}
+}
@@ -0,0 +1,161 @@
+package cacher
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const numRemovalReasons = int(RemovalClosed) + 1
+
+// Stats is a snapshot of the counters of a Cacher instance,
+// returned by Cacher.Stats.
+//
+// Hits and Misses count Get calls which found or didn't find
+// their key, a Get which found an expired key counts as a miss
+// and is also counted in ExpiredOnRead.
+//
+// Sets counts the values stored in the cache.
+//
+// Removals counts the keys which left the cache, grouped by the
+// reason of their removal, eg. Removals[RemovalDeleted] counts
+// the keys deleted via Delete and DeleteSome.
+//
+// CleanerRuns and CleanerDuration count the passes of the
+// cleaner and the total time spent in them.
+type Stats struct {
+	Hits            uint64
+	Misses          uint64
+	ExpiredOnRead   uint64
+	Sets            uint64
+	Removals        map[RemovalReason]uint64
+	CleanerRuns     uint64
+	CleanerDuration time.Duration
+}
+
+// Evictions returns the number of keys the Cacher removed by
+// itself, ie. expired keys and keys evicted due to MaxKeys.
+func (s Stats) Evictions() uint64 {
+	var n uint64
+	for reason, count := range s.Removals {
+		if reason.IsEviction() {
+			n += count
+		}
+	}
+	return n
+}
+
+// HitRatio returns the ratio of Get calls which found their key,
+// or 0 if there was no Get call.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+func (s *Stats) add(o Stats) {
+	s.Hits += o.Hits
+	s.Misses += o.Misses
+	s.ExpiredOnRead += o.ExpiredOnRead
+	s.Sets += o.Sets
+	for reason, count := range o.Removals {
+		s.Removals[reason] += count
+	}
+	s.CleanerRuns += o.CleanerRuns
+	s.CleanerDuration += o.CleanerDuration
+}
+
+// stats holds the counters of a Cacher, they're updated atomically
+// so that reads under the read lock don't need the write lock.
+type stats struct {
+	hits            uint64
+	misses          uint64
+	expiredOnRead   uint64
+	sets            uint64
+	removals        [numRemovalReasons]uint64
+	cleanerRuns     uint64
+	cleanerDuration int64
+}
+
+func (s *stats) hit() {
+	atomic.AddUint64(&s.hits, 1)
+}
+
+func (s *stats) miss(expired bool) {
+	atomic.AddUint64(&s.misses, 1)
+	if expired {
+		atomic.AddUint64(&s.expiredOnRead, 1)
+	}
+}
+
+func (s *stats) set() {
+	atomic.AddUint64(&s.sets, 1)
+}
+
+func (s *stats) removed(reason RemovalReason, n int) {
+	atomic.AddUint64(&s.removals[reason], uint64(n))
+}
+
+func (s *stats) cleaned(d time.Duration) {
+	atomic.AddUint64(&s.cleanerRuns, 1)
+	atomic.AddInt64(&s.cleanerDuration, int64(d))
+}
+
+func (s *stats) snapshot() Stats {
+	res := Stats{
+		Hits:            atomic.LoadUint64(&s.hits),
+		Misses:          atomic.LoadUint64(&s.misses),
+		ExpiredOnRead:   atomic.LoadUint64(&s.expiredOnRead),
+		Sets:            atomic.LoadUint64(&s.sets),
+		Removals:        make(map[RemovalReason]uint64, numRemovalReasons),
+		CleanerRuns:     atomic.LoadUint64(&s.cleanerRuns),
+		CleanerDuration: time.Duration(atomic.LoadInt64(&s.cleanerDuration)),
+	}
+	for i := range s.removals {
+		res.Removals[RemovalReason(i)] = atomic.LoadUint64(&s.removals[i])
+	}
+	return res
+}
+
+func (s *stats) reset() {
+	atomic.StoreUint64(&s.hits, 0)
+	atomic.StoreUint64(&s.misses, 0)
+	atomic.StoreUint64(&s.expiredOnRead, 0)
+	atomic.StoreUint64(&s.sets, 0)
+	for i := range s.removals {
+		atomic.StoreUint64(&s.removals[i], 0)
+	}
+	atomic.StoreUint64(&s.cleanerRuns, 0)
+	atomic.StoreInt64(&s.cleanerDuration, 0)
+}
+
+// Stats returns a snapshot of the counters of current Cacher
+// instance.
+func (c *Cacher[C, T]) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// ResetStats sets all the counters of current Cacher instance
+// back to zero.
+func (c *Cacher[C, T]) ResetStats() {
+	c.stats.reset()
+}
+
+// Stats returns a snapshot of the counters of current
+// ShardedCacher instance, summed over all of its shards.
+func (s *ShardedCacher[K, V]) Stats() Stats {
+	res := Stats{Removals: make(map[RemovalReason]uint64, numRemovalReasons)}
+	for _, c := range s.shards {
+		res.add(c.Stats())
+	}
+	return res
+}
+
+// ResetStats sets all the counters of every shard of current
+// ShardedCacher instance back to zero.
+func (s *ShardedCacher[K, V]) ResetStats() {
+	for _, c := range s.shards {
+		c.ResetStats()
+	}
+}
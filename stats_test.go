@@ -0,0 +1,45 @@
+package cacher
+
+import (
+	"testing"
+)
+
+func TestCacher_Stats(t *testing.T) {
+	c := NewCacher[int, int](&NewCacherOpts{MaxKeys: 2})
+	c.Set(1, 1)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+	c.Get(3)
+	c.Get(1)
+	c.Delete(3)
+	c.cleanExpired()
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 1 {
+		t.Errorf("Stats() hits, misses = %d, %d, want 1, 1", s.Hits, s.Misses)
+	}
+	if s.Sets != 4 {
+		t.Errorf("Stats().Sets = %d, want 4", s.Sets)
+	}
+	want := map[RemovalReason]uint64{
+		RemovalReplaced: 1,
+		RemovalCapacity: 1,
+		RemovalDeleted:  1,
+	}
+	for reason, count := range want {
+		if s.Removals[reason] != count {
+			t.Errorf("Stats().Removals[%s] = %d, want %d", reason, s.Removals[reason], count)
+		}
+	}
+	if s.Evictions() != 1 {
+		t.Errorf("Stats().Evictions() = %d, want 1", s.Evictions())
+	}
+	if s.CleanerRuns != 1 {
+		t.Errorf("Stats().CleanerRuns = %d, want 1", s.CleanerRuns)
+	}
+	c.ResetStats()
+	if s = c.Stats(); s.Hits != 0 || s.Sets != 0 || s.Evictions() != 0 {
+		t.Errorf("Stats() after ResetStats = %+v, want zero counters", s)
+	}
+}
@@ -16,3 +16,90 @@ const (
 	CleaningCentral
 	CleaningLocal
 )
+
+// EvictionMode selects the built-in EvictionPolicy used by a Cacher
+// bounded with NewCacherOpts.MaxKeys.
+type EvictionMode int
+
+const (
+	// EvictionLRU evicts the least recently used key first.
+	EvictionLRU EvictionMode = iota
+	// EvictionLFU evicts the least frequently used key first,
+	// breaking ties by recency.
+	EvictionLFU
+	// EvictionTinyLFU uses a small LRU admission window in front
+	// of a segmented LRU, and only admits keys leaving the window
+	// when they are estimated to be used more often than the key
+	// they would replace.
+	EvictionTinyLFU
+)
+
+// RemovalReason tells why a key left a Cacher, it's passed to
+// the listeners set via WithOnEvict and WithOnRemove.
+type RemovalReason int
+
+const (
+	// RemovalExpired means the TTL of the key had passed.
+	RemovalExpired RemovalReason = iota
+	// RemovalDeleted means the key was deleted explicitly.
+	RemovalDeleted
+	// RemovalReplaced means the value of the key was overwritten.
+	RemovalReplaced
+	// RemovalReset means the whole cache was reset.
+	RemovalReset
+	// RemovalCapacity means the key was evicted to keep the cache
+	// within NewCacherOpts.MaxKeys.
+	RemovalCapacity
+	// RemovalClosed means the cache was closed.
+	RemovalClosed
+)
+
+func (r RemovalReason) String() string {
+	switch r {
+	case RemovalExpired:
+		return "expired"
+	case RemovalDeleted:
+		return "deleted"
+	case RemovalReplaced:
+		return "replaced"
+	case RemovalReset:
+		return "reset"
+	case RemovalCapacity:
+		return "capacity"
+	case RemovalClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// IsEviction reports whether the key was removed automatically
+// by the Cacher rather than by its user.
+func (r RemovalReason) IsEviction() bool {
+	return r == RemovalExpired || r == RemovalCapacity
+}
+
+// LookupStatus tells the outcome of a Cacher.Lookup call.
+type LookupStatus int
+
+const (
+	// LookupMiss means the key wasn't present in the cache.
+	LookupMiss LookupStatus = iota
+	// LookupHit means the key was present and unexpired.
+	LookupHit
+	// LookupExpired means the key was present but had expired.
+	LookupExpired
+)
+
+func (s LookupStatus) String() string {
+	switch s {
+	case LookupMiss:
+		return "miss"
+	case LookupHit:
+		return "hit"
+	case LookupExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,369 @@
+package cacher
+
+import (
+	"sync"
+	"time"
+)
+
+// Store is a backing store of a Cacher, eg. a database, set via
+// WithStore. The Cacher reads the keys it misses through it, and
+// writes the keys set or deleted by its users to it.
+//
+// Its methods must be safe for concurrent use, and they must not
+// call back into the Cacher which writes to it.
+type Store[K comparable, V any] interface {
+	// Load returns the value of the input key, or ErrNotFound if
+	// the key isn't in the store.
+	Load(key K) (V, error)
+	// LoadMany returns the values of the input keys, keys which
+	// aren't in the store are left out of the returned map.
+	LoadMany(keys []K) (map[K]V, error)
+	// Save stores the input key-value pairs.
+	Save(items map[K]V) error
+	// Delete removes the input keys, missing keys are ignored.
+	Delete(keys []K) error
+}
+
+// storeRetryDelay is the delay before the first retry of a failed
+// write to the Store, it doubles with every retry.
+const storeRetryDelay = 50 * time.Millisecond
+
+// storeWrite is a pending write of a key to the Store.
+type storeWrite[C comparable, T any] struct {
+	key     C
+	val     T
+	deleted bool
+}
+
+// storeWriter writes the changes of a Cacher to its Store, either
+// right after each change (write-through) or periodically from a
+// goroutine (write-behind).
+type storeWriter[C comparable, T any] struct {
+	store   Store[C, T]
+	batch   int
+	retries int
+	onError func(error)
+	// mu guards turn and pending. It's never taken while holding
+	// the write lock of the Cacher, nor held during Store calls.
+	mu sync.Mutex
+	// turn is the ticket of the next write lock whose changes may
+	// be handed to the Store, see Cacher.writeSeq.
+	turn     uint64
+	turnCond *sync.Cond
+
+	// The following fields are only used in write-behind mode.
+	interval time.Duration
+	pending  map[C]storeWrite[C, T]
+	flushMu  sync.Mutex
+	kick     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func newStoreWriter[C comparable, T any](store Store[C, T], opts *NewCacherOpts) *storeWriter[C, T] {
+	w := &storeWriter[C, T]{
+		store:    store,
+		batch:    opts.StoreBatchSize,
+		retries:  opts.StoreRetries,
+		onError:  opts.OnStoreError,
+		interval: opts.WriteBehind,
+	}
+	w.turnCond = sync.NewCond(&w.mu)
+	if w.interval > 0 {
+		w.pending = make(map[C]storeWrite[C, T])
+		w.kick = make(chan struct{}, 1)
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+		go w.run()
+	}
+	return w
+}
+
+// queueWriteLocked queues a write of the input key to the Store of
+// current Cacher instance, if it has one. Caller must hold the
+// write lock, the write is handed to the Store once it's released
+// via unlock. Closed Cachers don't write to their Store anymore.
+func (c *Cacher[C, T]) queueWriteLocked(w storeWrite[C, T]) {
+	if c.store == nil || c.status == cacherDeleted {
+		return
+	}
+	c.writes = append(c.writes, w)
+}
+
+// deleteStoredLocked deletes the input key from the cache and the
+// Store, unlike deleteLocked it reaches the Store even if the key
+// isn't cached. Caller must hold the write lock.
+func (c *Cacher[C, T]) deleteStoredLocked(key C) {
+	if _, ok := c.cacheMap[key]; ok {
+		c.deleteLocked(key, RemovalDeleted)
+		return
+	}
+	c.invalidateLoadLocked(key)
+	c.queueWriteLocked(storeWrite[C, T]{key: key, deleted: true})
+}
+
+// Flush writes the changes queued for write-behind to the Store
+// of current Cacher instance right away, and returns the first
+// error the Store returned. It's a no-op without write-behind.
+func (c *Cacher[C, T]) Flush() error {
+	if c.store == nil || c.store.interval <= 0 {
+		return nil
+	}
+	return c.store.flush()
+}
+
+// GetOrLoadMany is used to get the values of the input keys,
+// loading the missing ones with a single Store.LoadMany call.
+// Keys which are neither cached nor in the Store are left out of
+// the returned map. Without a Store, the missing keys are loaded
+// one by one via GetOrLoad with the default loader.
+//
+// Unlike GetOrLoad, concurrent calls don't share their loads.
+func (c *Cacher[C, T]) GetOrLoadMany(keys []C) (map[C]T, error) {
+	found, missing := c.GetMany(keys)
+	if len(missing) == 0 {
+		return found, nil
+	}
+	if c.Closed() {
+		return found, ErrCacherClosed
+	}
+	if c.store == nil {
+		for _, key := range missing {
+			val, err := c.GetOrLoad(key, nil)
+			if err != nil {
+				return found, err
+			}
+			found[key] = val
+		}
+		return found, nil
+	}
+	c.mutex.Lock()
+	marks := make([]*pendingLoad, len(missing))
+	for i, key := range missing {
+		marks[i] = c.beginLoadLocked(key)
+	}
+	c.mutex.Unlock()
+
+	var loaded map[C]T
+	defer func() {
+		c.mutex.Lock()
+		defer c.unlock()
+		for i, key := range missing {
+			// Don't overwrite a value which was set or deleted while
+			// we were loading.
+			stale := c.endLoadLocked(key, marks[i])
+			val, ok := loaded[key]
+			if stale || !ok {
+				continue
+			}
+			v := c.packValue(val, nil, false)
+			v.synced = true
+			c.setLocked(key, v)
+		}
+	}()
+	loaded, err := c.store.store.LoadMany(missing)
+	if err != nil {
+		loaded = nil
+		return found, err
+	}
+	for key, val := range loaded {
+		found[key] = val
+	}
+	return found, nil
+}
+
+// write hands the input changes, made under the write lock which
+// got the seq ticket, to the Store. It waits for the changes of the
+// earlier tickets to be handed first, so that the Store sees the
+// changes in the order they were made.
+func (w *storeWriter[C, T]) write(seq uint64, writes []storeWrite[C, T]) {
+	w.mu.Lock()
+	for w.turn != seq {
+		w.turnCond.Wait()
+	}
+	if w.interval > 0 {
+		for _, sw := range writes {
+			w.pending[sw.key] = sw
+		}
+		full := w.batch > 0 && len(w.pending) >= w.batch
+		w.advanceLocked()
+		w.mu.Unlock()
+		if full {
+			select {
+			case w.kick <- struct{}{}:
+			default:
+			}
+		}
+		return
+	}
+	w.mu.Unlock()
+	errs := func() []error {
+		// The turn is only passed on once the Store calls are done,
+		// a later ticket could otherwise overtake them.
+		defer func() {
+			w.mu.Lock()
+			w.advanceLocked()
+			w.mu.Unlock()
+		}()
+		coalesced := make(map[C]storeWrite[C, T], len(writes))
+		for _, sw := range writes {
+			coalesced[sw.key] = sw
+		}
+		return w.apply(coalesced)
+	}()
+	// Reported once the turn is passed on, so that OnStoreError may
+	// write to the Cacher.
+	w.report(errs)
+}
+
+// advanceLocked passes the turn on to the next ticket, caller must
+// hold w.mu.
+func (w *storeWriter[C, T]) advanceLocked() {
+	w.turn++
+	w.turnCond.Broadcast()
+}
+
+// wait blocks until the changes of the tickets before seq have been
+// handed to the Store.
+func (w *storeWriter[C, T]) wait(seq uint64) {
+	w.mu.Lock()
+	for w.turn != seq {
+		w.turnCond.Wait()
+	}
+	w.mu.Unlock()
+}
+
+func (w *storeWriter[C, T]) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.kick:
+		case <-w.stop:
+			w.flush()
+			return
+		}
+		w.flush()
+	}
+}
+
+// flush writes the pending changes to the Store.
+func (w *storeWriter[C, T]) flush() error {
+	// Flushes must not overtake each other, or an older value of
+	// a key could be written after a newer one.
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = make(map[C]storeWrite[C, T])
+	w.mu.Unlock()
+	return w.report(w.apply(pending))
+}
+
+// close waits for the changes of the tickets before seq, then
+// flushes the pending changes and stops the write-behind goroutine.
+// It must be called only once.
+func (w *storeWriter[C, T]) close(seq uint64) {
+	w.wait(seq)
+	if w.interval > 0 {
+		close(w.stop)
+		<-w.done
+	}
+}
+
+// report passes the input errors to w.onError, and returns the
+// first of them.
+func (w *storeWriter[C, T]) report(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if w.onError != nil {
+		for _, err := range errs {
+			w.onError(err)
+		}
+	}
+	return errs[0]
+}
+
+// apply writes the input changes to the Store in batches of at
+// most w.batch keys, retrying each batch up to w.retries times.
+// Batches which still fail are dropped, and their errors returned
+// to be reported.
+func (w *storeWriter[C, T]) apply(writes map[C]storeWrite[C, T]) []error {
+	var errs []error
+	report := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	saves := make(map[C]T)
+	var deletes []C
+	for key, sw := range writes {
+		if sw.deleted {
+			deletes = append(deletes, key)
+		} else {
+			saves[key] = sw.val
+		}
+		if w.batch > 0 && len(saves) == w.batch {
+			report(w.retry(func() error { return w.store.Save(saves) }))
+			saves = make(map[C]T)
+		}
+		if w.batch > 0 && len(deletes) == w.batch {
+			report(w.retry(func() error { return w.store.Delete(deletes) }))
+			deletes = nil
+		}
+	}
+	if len(saves) != 0 {
+		report(w.retry(func() error { return w.store.Save(saves) }))
+	}
+	if len(deletes) != 0 {
+		report(w.retry(func() error { return w.store.Delete(deletes) }))
+	}
+	return errs
+}
+
+func (w *storeWriter[C, T]) retry(fn func() error) error {
+	delay := storeRetryDelay
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= w.retries {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// Flush writes the changes queued for write-behind in every shard
+// to the Store, see Cacher.Flush.
+func (s *ShardedCacher[K, V]) Flush() error {
+	var firstErr error
+	for _, c := range s.shards {
+		if err := c.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// GetOrLoadMany is used to get the values of the input keys,
+// loading the missing ones from the Store, see
+// Cacher.GetOrLoadMany. Each shard loads its own keys.
+func (s *ShardedCacher[K, V]) GetOrLoadMany(keys []K) (map[K]V, error) {
+	res := make(map[K]V, len(keys))
+	for i, part := range s.splitKeys(keys) {
+		if len(part) == 0 {
+			continue
+		}
+		found, err := s.shards[i].GetOrLoadMany(part)
+		for k, v := range found {
+			res[k] = v
+		}
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
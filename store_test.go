@@ -0,0 +1,356 @@
+package cacher
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingStore wraps a MemoryStore, recording its writes and
+// failing the first fails of them.
+type recordingStore struct {
+	*MemoryStore[string, int]
+	mu     sync.Mutex
+	saves  []map[string]int
+	dels   [][]string
+	fails  int
+	writes int
+}
+
+func newRecordingStore() *recordingStore {
+	return &recordingStore{MemoryStore: NewMemoryStore[string, int]()}
+}
+
+func (s *recordingStore) fail() error {
+	s.writes++
+	if s.writes <= s.fails {
+		return errors.New("store is down")
+	}
+	return nil
+}
+
+func (s *recordingStore) Save(items map[string]int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	copied := make(map[string]int, len(items))
+	for k, v := range items {
+		copied[k] = v
+	}
+	s.saves = append(s.saves, copied)
+	return s.MemoryStore.Save(items)
+}
+
+func (s *recordingStore) Delete(keys []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	s.dels = append(s.dels, append([]string(nil), keys...))
+	return s.MemoryStore.Delete(keys)
+}
+
+func (s *recordingStore) numSaves() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.saves)
+}
+
+func TestCacher_StoreReadThrough(t *testing.T) {
+	store := newRecordingStore()
+	store.MemoryStore.Save(map[string]int{"a": 1, "b": 2, "c": 3})
+	c := NewCacher(nil, WithStore[string, int](store))
+
+	if v, err := c.GetOrLoad("a", nil); err != nil || v != 1 {
+		t.Errorf("Cacher.GetOrLoad() = %d, %v, want 1, nil", v, err)
+	}
+	if _, err := c.GetOrLoad("x", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Cacher.GetOrLoad() error = %v, want ErrNotFound", err)
+	}
+	got, err := c.GetOrLoadMany([]string{"a", "b", "c", "x"})
+	if err != nil || len(got) != 3 || got["c"] != 3 {
+		t.Errorf("Cacher.GetOrLoadMany() = %v, %v, want 3 pairs", got, err)
+	}
+	if n := store.numSaves(); n != 0 {
+		t.Errorf("loaded values were written back %d times", n)
+	}
+}
+
+func TestCacher_StoreWriteThrough(t *testing.T) {
+	store := newRecordingStore()
+	store.MemoryStore.Save(map[string]int{"uncached": 0})
+	clock := NewManualClock(time.Now())
+	c := NewCacher(&NewCacherOpts{MaxKeys: 2, Clock: clock}, WithStore[string, int](store))
+
+	c.Set("a", 1)
+	c.SetMany(map[string]int{"b": 2, "c": 3})
+	if v, err := store.Load("a"); err != nil || v != 1 {
+		t.Errorf("Store.Load(a) = %d, %v after Set, want 1, nil", v, err)
+	}
+	if n := store.numSaves(); n != 2 {
+		t.Errorf("Store.Save() called %d times, want 2", n)
+	}
+	// "a" was evicted from the cache, but must stay in the store.
+	if store.Len() != 4 {
+		t.Errorf("store has %d keys, want 4", store.Len())
+	}
+	c.Update("b", func(old int, ok bool) (int, bool) { return old + 1, true })
+	if v, _ := store.Load("b"); v != 3 {
+		t.Errorf("Store.Load(b) = %d after Update, want 3", v)
+	}
+	c.Delete("uncached")
+	c.DeleteSome(func(v int) bool { return v == 3 })
+	if store.Len() != 1 {
+		t.Errorf("store has %d keys after deletes, want only a", store.Len())
+	}
+}
+
+// slowStore is a MemoryStore whose saves block until release is
+// closed, saving is closed once the first one starts.
+type slowStore struct {
+	*MemoryStore[string, int]
+	once    sync.Once
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (s *slowStore) Save(items map[string]int) error {
+	s.once.Do(func() { close(s.saving) })
+	<-s.release
+	return s.MemoryStore.Save(items)
+}
+
+func TestCacher_StoreSlowSave(t *testing.T) {
+	store := &slowStore{
+		MemoryStore: NewMemoryStore[string, int](),
+		saving:      make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	c := NewCacher(nil, WithStore[string, int](store))
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		c.Set("a", 1)
+	}()
+	<-store.saving
+	// Waits for the save of a, which must not keep the cache locked.
+	go func() {
+		defer wg.Done()
+		c.Set("b", 2)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	got := make(chan bool)
+	go func() {
+		_, ok := c.Get("a")
+		got <- ok
+	}()
+	select {
+	case ok := <-got:
+		if !ok {
+			t.Errorf("Cacher.Get(a) missed during the save")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Cacher.Get() was blocked by a slow Store.Save()")
+	}
+	close(store.release)
+	wg.Wait()
+	if store.Len() != 2 {
+		t.Errorf("store has %d keys, want a and b", store.Len())
+	}
+}
+
+func TestCacher_StoreWriteBehind(t *testing.T) {
+	store := newRecordingStore()
+	store.fails = 1
+	var errs []error
+	c := NewCacher(&NewCacherOpts{
+		WriteBehind:  time.Hour,
+		StoreRetries: 1,
+		OnStoreError: func(err error) { errs = append(errs, err) },
+	}, WithStore[string, int](store))
+	for i := 0; i < 10; i++ {
+		c.Set("a", i)
+	}
+	c.Set("b", 1)
+	c.Delete("b")
+	if store.Len() != 0 {
+		t.Fatalf("write-behind wrote before the flush")
+	}
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Cacher.Flush() error = %v", err)
+	}
+	if len(store.saves) != 1 || len(store.saves[0]) != 1 || store.saves[0]["a"] != 9 {
+		t.Errorf("Store.Save() calls = %v, want one coalesced save of a=9", store.saves)
+	}
+	if len(store.dels) != 1 || len(errs) != 0 {
+		t.Errorf("Store.Delete() calls = %v, errors = %v, want one delete and no error", store.dels, errs)
+	}
+
+	c.Set("c", 3)
+	if err := c.Close(); err != nil {
+		t.Fatalf("Cacher.Close() error = %v", err)
+	}
+	if v, err := store.Load("c"); err != nil || v != 3 {
+		t.Errorf("Store.Load(c) = %d, %v after Close, want 3, nil", v, err)
+	}
+}
+
+func TestCacher_StoreWriteBehindBatch(t *testing.T) {
+	store := newRecordingStore()
+	c := NewCacher(&NewCacherOpts{
+		WriteBehind:    time.Hour,
+		StoreBatchSize: 2,
+	}, WithStore[string, int](store))
+	defer c.Close()
+	c.Set("a", 1)
+	c.Set("b", 2)
+	deadline := time.Now().Add(time.Second)
+	for store.numSaves() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("a full batch wasn't written")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCacher_StoreAfterClose(t *testing.T) {
+	store := newRecordingStore()
+	store.MemoryStore.Save(map[string]int{"a": 1, "b": 2})
+	c := NewCacher(&NewCacherOpts{WriteBehind: time.Hour}, WithStore[string, int](store))
+	c.Close()
+	c.Delete("a")
+	c.DeleteMany([]string{"b"})
+	if len(c.store.pending) != 0 {
+		t.Errorf("%d writes were queued after Close", len(c.store.pending))
+	}
+	if len(store.dels) != 0 || store.Len() != 2 {
+		t.Errorf("Store.Delete() calls = %v after Close, want none", store.dels)
+	}
+}
+
+func TestCacher_StoreErrors(t *testing.T) {
+	store := newRecordingStore()
+	store.fails = 1
+	var errs []error
+	c := NewCacher(&NewCacherOpts{
+		OnStoreError: func(err error) { errs = append(errs, err) },
+	}, WithStore[string, int](store))
+	c.Set("a", 1)
+	if len(errs) != 1 {
+		t.Errorf("OnStoreError called %d times, want 1", len(errs))
+	}
+	// The cache is updated even if the store isn't.
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Cacher.Get(a) = %d, %v, want 1, true", v, ok)
+	}
+}
+
+func TestCacher_StoreErrorCallsBack(t *testing.T) {
+	store := newRecordingStore()
+	store.fails = 1
+	var c *Cacher[string, int]
+	c = NewCacher(&NewCacherOpts{
+		OnStoreError: func(error) { c.Set("failed", 1) },
+	}, WithStore[string, int](store))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Set("a", 1)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Cacher.Set() deadlocked when OnStoreError called back into the Cacher")
+	}
+	if v, err := store.Load("failed"); err != nil || v != 1 {
+		t.Errorf("Store.Load(failed) = %d, %v, want 1, nil", v, err)
+	}
+}
+
+// slowLoadStore is a MemoryStore whose LoadMany blocks until
+// release is closed, loading is closed once it starts.
+type slowLoadStore struct {
+	*MemoryStore[string, int]
+	loading chan struct{}
+	release chan struct{}
+}
+
+func (s *slowLoadStore) LoadMany(keys []string) (map[string]int, error) {
+	close(s.loading)
+	<-s.release
+	return s.MemoryStore.LoadMany(keys)
+}
+
+func TestCacher_GetOrLoadManyConcurrentSet(t *testing.T) {
+	store := &slowLoadStore{
+		MemoryStore: NewMemoryStore[string, int](),
+		loading:     make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	store.MemoryStore.Save(map[string]int{"a": 1, "b": 2})
+	c := NewCacher(nil, WithStore[string, int](store))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.GetOrLoadMany([]string{"a", "b"})
+	}()
+	<-store.loading
+	c.Set("a", 10)
+	close(store.release)
+	<-done
+	if v, _ := c.Get("a"); v != 10 {
+		t.Errorf("Cacher.Get(a) = %d, want the 10 set during the load", v)
+	}
+	if v, _ := c.Get("b"); v != 2 {
+		t.Errorf("Cacher.Get(b) = %d, want the loaded 2", v)
+	}
+	if v, _ := store.Load("a"); v != 10 {
+		t.Errorf("Store.Load(a) = %d, want 10", v)
+	}
+}
+
+func TestShardedCacher_Store(t *testing.T) {
+	store := NewMemoryStore[string, int]()
+	store.Save(map[string]int{"a": 1, "b": 2})
+	s := NewShardedCacher(4, nil, &NewCacherOpts{WriteBehind: time.Hour}, WithStore[string, int](store))
+	if got, err := s.GetOrLoadMany([]string{"a", "b"}); err != nil || len(got) != 2 {
+		t.Errorf("ShardedCacher.GetOrLoadMany() = %v, %v, want 2 pairs", got, err)
+	}
+	s.Set("c", 3)
+	s.Delete("a")
+	if err := s.Flush(); err != nil {
+		t.Fatalf("ShardedCacher.Flush() error = %v", err)
+	}
+	if _, err := store.Load("a"); !errors.Is(err, ErrNotFound) || store.Len() != 2 {
+		t.Errorf("store has %d keys after Flush, want b and c", store.Len())
+	}
+	s.Close()
+}
+
+func TestFileStore(t *testing.T) {
+	for _, codec := range []Codec{GobCodec, JSONCodec} {
+		path := filepath.Join(t.TempDir(), "store")
+		store := NewFileStore[string, int](path, codec)
+		if _, err := store.Load("a"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("FileStore.Load() error = %v on a missing file, want ErrNotFound", err)
+		}
+		if err := store.Save(map[string]int{"a": 1, "b": 2, "c": 3}); err != nil {
+			t.Fatalf("FileStore.Save() error = %v", err)
+		}
+		if err := store.Delete([]string{"b"}); err != nil {
+			t.Fatalf("FileStore.Delete() error = %v", err)
+		}
+		reopened := NewFileStore[string, int](path, codec)
+		got, err := reopened.LoadMany([]string{"a", "b", "c"})
+		if err != nil || len(got) != 2 || got["a"] != 1 || got["c"] != 3 {
+			t.Errorf("FileStore.LoadMany() = %v, %v, want map[a:1 c:3]", got, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package cacher
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MemoryStore is a Store which keeps its pairs in a map, it's
+// meant for tests and as a reference implementation of Store.
+type MemoryStore[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]V
+}
+
+// NewMemoryStore creates a new empty MemoryStore instance.
+func NewMemoryStore[K comparable, V any]() *MemoryStore[K, V] {
+	return &MemoryStore[K, V]{items: make(map[K]V)}
+}
+
+// Load returns the value of the input key, or ErrNotFound.
+func (s *MemoryStore[K, V]) Load(key K) (V, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	val, ok := s.items[key]
+	if !ok {
+		return val, ErrNotFound
+	}
+	return val, nil
+}
+
+// LoadMany returns the values of the input keys which are present.
+func (s *MemoryStore[K, V]) LoadMany(keys []K) (map[K]V, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if val, ok := s.items[key]; ok {
+			res[key] = val
+		}
+	}
+	return res, nil
+}
+
+// Save stores the input key-value pairs.
+func (s *MemoryStore[K, V]) Save(items map[K]V) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, val := range items {
+		s.items[key] = val
+	}
+	return nil
+}
+
+// Delete removes the input keys.
+func (s *MemoryStore[K, V]) Delete(keys []K) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.items, key)
+	}
+	return nil
+}
+
+// Len returns the number of pairs in current MemoryStore.
+func (s *MemoryStore[K, V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.items)
+}
+
+// FileStore is a Store which keeps all of its pairs in a single
+// file, encoded with a Codec. Every write rewrites the whole file,
+// atomically via a rename, so it's only meant for tests, small
+// data sets and as a reference implementation of Store.
+type FileStore[K comparable, V any] struct {
+	mu    sync.Mutex
+	path  string
+	codec Codec
+}
+
+// NewFileStore creates a new FileStore instance which keeps its
+// pairs in the file at the input path, encoded with the input
+// codec or GobCodec if it's nil. The file is created by the first
+// write.
+func NewFileStore[K comparable, V any](path string, codec Codec) *FileStore[K, V] {
+	if codec == nil {
+		codec = GobCodec
+	}
+	return &FileStore[K, V]{path: path, codec: codec}
+}
+
+// Load returns the value of the input key, or ErrNotFound.
+func (s *FileStore[K, V]) Load(key K) (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.read()
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	val, ok := items[key]
+	if !ok {
+		return val, ErrNotFound
+	}
+	return val, nil
+}
+
+// LoadMany returns the values of the input keys which are present.
+func (s *FileStore[K, V]) LoadMany(keys []K) (map[K]V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if val, ok := items[key]; ok {
+			res[key] = val
+		}
+	}
+	return res, nil
+}
+
+// Save stores the input key-value pairs.
+func (s *FileStore[K, V]) Save(items map[K]V) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.read()
+	if err != nil {
+		return err
+	}
+	for key, val := range items {
+		stored[key] = val
+	}
+	return s.write(stored)
+}
+
+// Delete removes the input keys.
+func (s *FileStore[K, V]) Delete(keys []K) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.read()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		delete(stored, key)
+	}
+	return s.write(stored)
+}
+
+func (s *FileStore[K, V]) read() (map[K]V, error) {
+	items := make(map[K]V)
+	f, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return items, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := s.codec.NewDecoder(f).Decode(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *FileStore[K, V]) write(items map[K]V) error {
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := s.codec.NewEncoder(f).Encode(items); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
@@ -0,0 +1,232 @@
+package cacher
+
+import (
+	"container/list"
+	"sync"
+)
+
+const (
+	segWindow uint8 = iota
+	segProbation
+	segProtected
+)
+
+// tinyLFUPolicy is a W-TinyLFU eviction policy.
+//
+// New keys enter a small LRU window (1% of the capacity). Keys
+// pushed out of the window become candidates for the main space,
+// a segmented LRU made of a probation and a protected segment.
+// A candidate is only admitted over the probation victim if the
+// count-min sketch estimates that it's used more frequently,
+// which keeps hot keys safe from scans of one-off keys.
+type tinyLFUPolicy[C comparable] struct {
+	mu           sync.Mutex
+	sketch       *countMinSketch
+	window       *list.List
+	probation    *list.List
+	protected    *list.List
+	nodes        map[C]*list.Element
+	windowCap    int
+	protectedCap int
+	// candidate is the last key moved from the window to the
+	// probation segment which hasn't been judged yet.
+	candidate *list.Element
+}
+
+type tinyLFUNode[C comparable] struct {
+	key C
+	seg uint8
+}
+
+// NewTinyLFUPolicy returns a W-TinyLFU EvictionPolicy sized for
+// a Cacher holding at most maxKeys keys.
+func NewTinyLFUPolicy[C comparable](maxKeys int) EvictionPolicy[C] {
+	windowCap := maxKeys / 100
+	if windowCap < 1 {
+		windowCap = 1
+	}
+	return &tinyLFUPolicy[C]{
+		sketch:       newCountMinSketch(maxKeys),
+		window:       list.New(),
+		probation:    list.New(),
+		protected:    list.New(),
+		nodes:        make(map[C]*list.Element),
+		windowCap:    windowCap,
+		protectedCap: (maxKeys - windowCap) * 8 / 10,
+	}
+}
+
+func (p *tinyLFUPolicy[C]) segment(seg uint8) *list.List {
+	switch seg {
+	case segProbation:
+		return p.probation
+	case segProtected:
+		return p.protected
+	default:
+		return p.window
+	}
+}
+
+// move unlinks e from its segment and pushes its key to the front
+// of the target segment, returning the new element.
+func (p *tinyLFUPolicy[C]) move(e *list.Element, seg uint8) *list.Element {
+	n := e.Value.(*tinyLFUNode[C])
+	p.segment(n.seg).Remove(e)
+	n.seg = seg
+	ne := p.segment(seg).PushFront(n)
+	p.nodes[n.key] = ne
+	if p.candidate == e {
+		p.candidate = ne
+	}
+	return ne
+}
+
+func (p *tinyLFUPolicy[C]) OnInsert(key C) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sketch.increment(hashKey(key))
+	if e, ok := p.nodes[key]; ok {
+		p.touch(e)
+		return
+	}
+	p.nodes[key] = p.window.PushFront(&tinyLFUNode[C]{key: key, seg: segWindow})
+	if p.window.Len() > p.windowCap {
+		p.candidate = p.move(p.window.Back(), segProbation)
+	}
+}
+
+func (p *tinyLFUPolicy[C]) OnAccess(key C) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sketch.increment(hashKey(key))
+	if e, ok := p.nodes[key]; ok {
+		p.touch(e)
+	}
+}
+
+// touch records a hit on an element, promoting probation keys to
+// the protected segment and demoting protected keys on overflow.
+func (p *tinyLFUPolicy[C]) touch(e *list.Element) {
+	switch e.Value.(*tinyLFUNode[C]).seg {
+	case segWindow:
+		p.window.MoveToFront(e)
+	case segProbation:
+		// A hit proves the candidate deserves its place.
+		if p.candidate == e {
+			p.candidate = nil
+		}
+		p.move(e, segProtected)
+		if p.protected.Len() > p.protectedCap {
+			p.move(p.protected.Back(), segProbation)
+		}
+	case segProtected:
+		p.protected.MoveToFront(e)
+	}
+}
+
+func (p *tinyLFUPolicy[C]) OnDelete(key C) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	if p.candidate == e {
+		p.candidate = nil
+	}
+	p.segment(e.Value.(*tinyLFUNode[C]).seg).Remove(e)
+	delete(p.nodes, key)
+}
+
+func (p *tinyLFUPolicy[C]) Victim() (key C, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var v *list.Element
+	switch {
+	case p.probation.Len() > 0:
+		v = p.probation.Back()
+	case p.protected.Len() > 0:
+		v = p.protected.Back()
+	default:
+		v = p.window.Back()
+	}
+	if v == nil {
+		return
+	}
+	if cand := p.candidate; cand != nil {
+		p.candidate = nil
+		if cand != v {
+			cKey := cand.Value.(*tinyLFUNode[C]).key
+			vKey := v.Value.(*tinyLFUNode[C]).key
+			if p.sketch.estimate(hashKey(cKey)) <= p.sketch.estimate(hashKey(vKey)) {
+				return cKey, true
+			}
+		}
+	}
+	return v.Value.(*tinyLFUNode[C]).key, true
+}
+
+const sketchDepth = 4
+
+// countMinSketch is a frequency estimator with 4 bit saturating
+// counters which are halved periodically so that old popularity
+// fades away.
+type countMinSketch struct {
+	rows       [sketchDepth][]uint8
+	mask       uint64
+	additions  int
+	sampleSize int
+}
+
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := 16
+	for width < capacity {
+		width <<= 1
+	}
+	s := &countMinSketch{
+		mask:       uint64(width - 1),
+		sampleSize: 10 * width,
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *countMinSketch) index(h uint64, row int) uint64 {
+	h += uint64(row) * (h>>32 | 1)
+	return mix64(h) & s.mask
+}
+
+func (s *countMinSketch) increment(h uint64) {
+	for i := range s.rows {
+		idx := s.index(h, i)
+		if s.rows[i][idx] < 15 {
+			s.rows[i][idx]++
+		}
+	}
+	s.additions++
+	if s.additions >= s.sampleSize {
+		s.reset()
+	}
+}
+
+func (s *countMinSketch) estimate(h uint64) uint8 {
+	min := uint8(15)
+	for i := range s.rows {
+		if c := s.rows[i][s.index(h, i)]; c < min {
+			min = c
+		}
+	}
+	return min
+}
+
+// reset halves every counter, aging the collected frequencies.
+func (s *countMinSketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
@@ -0,0 +1,172 @@
+package cacher
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Key2 is a composite key made of 2 values, it can be used as the
+// key type of a Cacher directly, eg. Cacher[Key2[int64, string], T],
+// which avoids formatting the values into a string on every call
+// like PolyKeyer does.
+//
+// Use Format and ParseKey2 to convert it to and from the string
+// keys of a PolyKeyer with 2 extra keys.
+type Key2[A, B comparable] struct {
+	First  A
+	Second B
+}
+
+// NewKey2 creates a new Key2 out of the input values.
+func NewKey2[A, B comparable](a A, b B) Key2[A, B] {
+	return Key2[A, B]{First: a, Second: b}
+}
+
+// Format returns the string key the input PolyKeyer creates out
+// of the values of current Key2, the PolyKeyer must take 2 extra
+// keys. Eg: NewKey2(101, "private").Format(NewPolyKeyer("chat", 2))
+// returns "chat.101.private".
+func (k Key2[A, B]) Format(keyer *PolyKeyer) string {
+	return keyer.New(formatKeyPart(k.First), formatKeyPart(k.Second))
+}
+
+func (k Key2[A, B]) hash() uint64 {
+	return mix64(hashKey(k.First)*31 + hashKey(k.Second))
+}
+
+// ParseKey2 converts a string key created by the input PolyKeyer
+// back into a Key2, see Key2.Format. It returns an error wrapping
+// ErrInvalidKey if the key wasn't created by the PolyKeyer or its
+// parts can't be parsed into A and B.
+func ParseKey2[A, B comparable](keyer *PolyKeyer, key string) (Key2[A, B], error) {
+	var k Key2[A, B]
+	parts, err := keyer.Parse(key)
+	if err != nil {
+		return k, err
+	}
+	if len(parts) != 2 {
+		return k, fmt.Errorf("%w: %q has %d extra keys, want 2", ErrInvalidKey, key, len(parts))
+	}
+	if k.First, err = parseKeyPart[A](parts[0]); err != nil {
+		return k, err
+	}
+	k.Second, err = parseKeyPart[B](parts[1])
+	return k, err
+}
+
+// Key3 is a composite key made of 3 values, see Key2.
+type Key3[A, B, C comparable] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// NewKey3 creates a new Key3 out of the input values.
+func NewKey3[A, B, C comparable](a A, b B, c C) Key3[A, B, C] {
+	return Key3[A, B, C]{First: a, Second: b, Third: c}
+}
+
+// Format returns the string key the input PolyKeyer creates out
+// of the values of current Key3, the PolyKeyer must take 3 extra
+// keys.
+func (k Key3[A, B, C]) Format(keyer *PolyKeyer) string {
+	return keyer.New(formatKeyPart(k.First), formatKeyPart(k.Second), formatKeyPart(k.Third))
+}
+
+func (k Key3[A, B, C]) hash() uint64 {
+	return mix64((hashKey(k.First)*31+hashKey(k.Second))*31 + hashKey(k.Third))
+}
+
+// ParseKey3 converts a string key created by the input PolyKeyer
+// back into a Key3, see ParseKey2.
+func ParseKey3[A, B, C comparable](keyer *PolyKeyer, key string) (Key3[A, B, C], error) {
+	var k Key3[A, B, C]
+	parts, err := keyer.Parse(key)
+	if err != nil {
+		return k, err
+	}
+	if len(parts) != 3 {
+		return k, fmt.Errorf("%w: %q has %d extra keys, want 3", ErrInvalidKey, key, len(parts))
+	}
+	if k.First, err = parseKeyPart[A](parts[0]); err != nil {
+		return k, err
+	}
+	if k.Second, err = parseKeyPart[B](parts[1]); err != nil {
+		return k, err
+	}
+	k.Third, err = parseKeyPart[C](parts[2])
+	return k, err
+}
+
+// formatKeyPart formats a part of a composite key, strings and
+// integers are formatted without going through fmt.
+func formatKeyPart[T comparable](v T) string {
+	switch v := any(v).(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseKeyPart parses a part of a composite key formatted by
+// formatKeyPart. Types whose underlying type is string are set as
+// is, other types than integers and booleans are parsed with
+// fmt.Fscan, which must consume the whole part.
+func parseKeyPart[T comparable](s string) (T, error) {
+	var v T
+	var err error
+	switch p := any(&v).(type) {
+	case *string:
+		*p = s
+	case *int:
+		*p, err = strconv.Atoi(s)
+	case *int64:
+		*p, err = strconv.ParseInt(s, 10, 64)
+	case *int32:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 32)
+		*p = int32(n)
+	case *uint:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 0)
+		*p = uint(n)
+	case *uint64:
+		*p, err = strconv.ParseUint(s, 10, 64)
+	case *uint32:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 32)
+		*p = uint32(n)
+	case *bool:
+		*p, err = strconv.ParseBool(s)
+	default:
+		if rv := reflect.ValueOf(p).Elem(); rv.Kind() == reflect.String {
+			rv.SetString(s)
+			break
+		}
+		r := strings.NewReader(s)
+		if _, err = fmt.Fscan(r, &v); err == nil && r.Len() != 0 {
+			err = fmt.Errorf("unexpected %q after the value", s[len(s)-r.Len():])
+		}
+	}
+	if err != nil {
+		return v, fmt.Errorf("%w: can't parse %q as %T: %v", ErrInvalidKey, s, v, err)
+	}
+	return v, nil
+}
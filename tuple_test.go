@@ -0,0 +1,80 @@
+package cacher
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func ExampleKey2() {
+	c := NewCacher[Key2[int64, string], string](nil)
+	c.Set(NewKey2[int64](101, "private"), "King's Chat")
+
+	title, _ := c.Get(NewKey2[int64](101, "private"))
+	fmt.Println(title)
+	fmt.Println(NewKey2(101, "private").Format(NewPolyKeyer("chat", 2)))
+	// Output: King's Chat
+	// chat.101.private
+}
+
+func TestKey2_FormatParse(t *testing.T) {
+	keyer := NewPolyKeyer("chat", 2)
+	k := NewKey2[int64, string](-101, "a.b")
+	key := k.Format(keyer)
+	if key != keyer.New("-101", "a.b") {
+		t.Errorf("Key2.Format() = %q, want the key of the PolyKeyer", key)
+	}
+	got, err := ParseKey2[int64, string](keyer, key)
+	if err != nil || got != k {
+		t.Errorf("ParseKey2(%q) = %v, %v, want %v, nil", key, got, err, k)
+	}
+	if _, err := ParseKey2[int64, string](keyer, "chat.x.private"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ParseKey2() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := ParseKey2[int64, string](NewPolyKeyer("chat", 3), "chat.1.2.3"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ParseKey2() with 3 extra keys error = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestKey3_FormatParse(t *testing.T) {
+	keyer := NewPolyKeyer("member", 3)
+	k := NewKey3[uint32, bool, float64](7, true, 1.5)
+	key := k.Format(keyer)
+	// The float has a dot in it, which must be escaped.
+	if want := `member.7.true.1\.5`; key != want {
+		t.Errorf("Key3.Format() = %q, want %q", key, want)
+	}
+	got, err := ParseKey3[uint32, bool, float64](keyer, key)
+	if err != nil || got != k {
+		t.Errorf("ParseKey3() = %v, %v, want %v, nil", got, err, k)
+	}
+}
+
+type keyName string
+
+func TestParseKey2_Spaces(t *testing.T) {
+	keyer := NewPolyKeyer("u", 2)
+	got, err := ParseKey2[keyName, int](keyer, "u.john doe.1")
+	if want := NewKey2[keyName]("john doe", 1); err != nil || got != want {
+		t.Errorf("ParseKey2() = %v, %v, want %v, nil", got, err, want)
+	}
+	// Only a part of the float would be parsed.
+	if _, err := ParseKey2[float64, int](keyer, "u.1 2.1"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ParseKey2() error = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestShardedCacher_TupleKeys(t *testing.T) {
+	s := NewShardedCacher[Key2[int, string], int](4, nil, nil)
+	for i := 0; i < 100; i++ {
+		s.Set(NewKey2(i, "a"), i)
+	}
+	for i := 0; i < 100; i++ {
+		if v, ok := s.Get(NewKey2(i, "a")); !ok || v != i {
+			t.Fatalf("ShardedCacher.Get(%d) = %d, %v, want %d, true", i, v, ok, i)
+		}
+	}
+	if hashKey(NewKey2(1, "a")) == hashKey(NewKey2(1, "b")) {
+		t.Errorf("hashKey() is the same for different Key2 values")
+	}
+}
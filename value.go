@@ -1,22 +1,90 @@
 package cacher
 
+import (
+	"sync/atomic"
+	"time"
+)
+
+// value is an entry of the cache map. Everything but the expiry
+// and the last access time is immutable once the value is stored,
+// while those two may be updated by Get calls holding only the
+// read lock, hence they're always accessed atomically.
+//
+// All the times are unix times in nanoseconds.
 type value[T any] struct {
-	// expiry int64
-	val T
-	evictibleValue
+	// expiry is the time at which the value expires, 0 means that
+	// it never expires. The atomically accessed fields come first
+	// so that they stay 64 bit aligned.
+	expiry int64
+	// lastAccess is the time of the last Get of the value, 0 means
+	// that it was never read.
+	lastAccess int64
+	created    int64
+	// ttl is used to renew the expiry when revaluate is set.
+	ttl time.Duration
+	// loadTime is how long the loader took to produce the value,
+	// 0 if the value wasn't loaded.
+	loadTime time.Duration
+	// synced is set if the value came from the loader or the Store,
+	// so it doesn't need to be written to the Store.
+	synced    bool
+	revaluate bool
+	permanent bool
+	val       T
 }
 
-// revaluate, ttl
-func (v *value[T]) get() (value T, expired bool) {
-	if v.isExpired(false) {
+// get returns the value along with whether it has expired at the
+// input time, and renews its expiry if revaluation is on.
+func (v *value[T]) get(now int64) (value T, expired bool) {
+	expiry := atomic.LoadInt64(&v.expiry)
+	if expiry != 0 && expiry <= now {
 		expired = true
 		return
 	}
-	value = v.val
-	return
+	atomic.StoreInt64(&v.lastAccess, now)
+	if expiry != 0 && v.revaluate {
+		// Losing the race means someone else has just renewed it.
+		atomic.CompareAndSwapInt64(&v.expiry, expiry, now+int64(v.ttl))
+	}
+	return v.val, false
 }
 
 func (v *value[T]) getWithoutExpiry() (value T) {
 	value = v.val
 	return
 }
+
+// isExpired reports whether the value has expired at the input
+// time, without renewing it.
+func (v *value[T]) isExpired(now int64) bool {
+	expiry := atomic.LoadInt64(&v.expiry)
+	return expiry != 0 && expiry <= now
+}
+
+func (v *value[T]) getExpiry() int64 {
+	return atomic.LoadInt64(&v.expiry)
+}
+
+func (v *value[T]) setExpiry(expiry int64) {
+	atomic.StoreInt64(&v.expiry, expiry)
+}
+
+func (v *value[T]) getLastAccess() int64 {
+	return atomic.LoadInt64(&v.lastAccess)
+}
+
+// clone returns a copy of the value which can be modified before
+// being stored in place of the original one.
+func (v *value[T]) clone() *value[T] {
+	return &value[T]{
+		expiry:     v.getExpiry(),
+		lastAccess: v.getLastAccess(),
+		created:    v.created,
+		ttl:        v.ttl,
+		loadTime:   v.loadTime,
+		synced:     v.synced,
+		revaluate:  v.revaluate,
+		permanent:  v.permanent,
+		val:        v.val,
+	}
+}